@@ -17,26 +17,98 @@
 package retry
 
 import (
-	"log"
+	"fmt"
 	"math"
 	"math/rand"
 	"time"
 )
 
+// BackoffStrategy is implemented by generators of sequences of intervals to
+// wait between attempts. Implementations are not expected to be thread-safe;
+// Retryable clones its strategy for every call to Retry.
+type BackoffStrategy interface {
+	// Next returns the next time interval to wait in the sequence.
+	Next() time.Duration
+	// Reset returns the strategy to the beginning of its sequence.
+	Reset()
+	// CloneStrategy returns an independent copy of the strategy, including
+	// its current position in the sequence.
+	CloneStrategy() BackoffStrategy
+}
+
+var _ BackoffStrategy = (*Backoff)(nil)
+
+// RandSource is a source of pseudo-random float64 values uniformly
+// distributed over [0.0, 1.0). *math/rand.Rand implements it.
+type RandSource interface {
+	Float64() float64
+}
+
+// randFloat64 returns a value from src, falling back to the global
+// math/rand source if src is nil.
+func randFloat64(src RandSource) float64 {
+	if src == nil {
+		return rand.Float64()
+	}
+	return src.Float64()
+}
+
+// cloneRand returns the source for a clone: a new one from newRand if it's
+// non-nil, otherwise src itself.
+func cloneRand(src RandSource, newRand func() RandSource) RandSource {
+	if newRand == nil {
+		return src
+	}
+	return newRand()
+}
+
+// JitterMode selects the randomization Backoff applies to the exponentially
+// growing intervals it generates.
+type JitterMode uint8
+
+const (
+	// JitterProportional adds or subtracts up to Jitter times the interval,
+	// jittering only down at MaxBackoff and only up at MinBackoff. This is
+	// the default.
+	JitterProportional JitterMode = iota
+	// JitterFull draws each interval uniformly from [0, interval). The
+	// result may be less than MinBackoff.
+	JitterFull
+	// JitterEqual keeps half of each interval fixed and draws the other half
+	// uniformly from [0, interval/2). The result may be less than
+	// MinBackoff.
+	JitterEqual
+)
+
 // Backoff contains the state implementing a generator returning a sequence of
 // intervals to wait.
 type Backoff struct {
 	step int
 	// If MaxBackoff == MinBackoff the backoff is constant.
 	// If MinBackoff > MaxBackoff, the implementation may generate a runtime panic.
+	// (Validate reports an error instead, as does Retryable.Retry.)
 	MaxBackoff time.Duration
 	MinBackoff time.Duration
 	// Jitter is the maximum value that may be added or substracted based on
 	// the output of a prng.
 	// Jitter should be < 1, and may produce /interesting/ results if it is > 1 or < 0.
+	// Jitter is only used by JitterProportional.
 	Jitter float64
 	// ExpFactor should be > 1, otherwise it will converge to 0.
 	ExpFactor float64
+	// JitterMode selects how intervals are randomized (see the JitterMode
+	// constants for details). The zero value is JitterProportional.
+	JitterMode JitterMode
+	// Rand is the source of randomness for jitter. If nil, the global
+	// math/rand source is used.
+	// Clones share the same Rand, so if a Retryable is used from multiple
+	// goroutines concurrently, Rand must be thread-safe (*math/rand.Rand
+	// is not); set NewRand instead to give each call its own source.
+	Rand RandSource
+	// NewRand, if non-nil, is called by Clone to create the clone's Rand,
+	// so that every call to Retryable.Retry (which clones B) gets a source
+	// of its own, which need not be thread-safe.
+	NewRand func() RandSource
 }
 
 // DefaultBackoff returns a reasonable default backoff instance.
@@ -49,7 +121,8 @@ func DefaultBackoff() Backoff {
 	}
 }
 
-// Clone returns a cloned copy of a Backoff struct.
+// Clone returns a cloned copy of a Backoff struct. If NewRand is set, the
+// clone gets a new Rand from it.
 func (b Backoff) Clone() Backoff {
 	return Backoff{
 		step:       b.step,
@@ -57,9 +130,19 @@ func (b Backoff) Clone() Backoff {
 		MinBackoff: b.MinBackoff,
 		Jitter:     b.Jitter,
 		ExpFactor:  b.ExpFactor,
+		JitterMode: b.JitterMode,
+		Rand:       cloneRand(b.Rand, b.NewRand),
+		NewRand:    b.NewRand,
 	}
 }
 
+// CloneStrategy implements BackoffStrategy, returning a pointer to a Clone of
+// the receiver.
+func (b Backoff) CloneStrategy() BackoffStrategy {
+	c := b.Clone()
+	return &c
+}
+
 // Get a random float64 between -b.Jitter and +b.Jitter.
 func (b Backoff) jitter() float64 {
 
@@ -67,19 +150,19 @@ func (b Backoff) jitter() float64 {
 	// 2 to move it to the interval [-1.0, 1.0), which is more suitable for
 	// jitter, as we want equal probabilities on either side of the basic
 	// exponential backoff.
-	return b.Jitter * (rand.Float64() - 0.5) * 2
+	return b.Jitter * (randFloat64(b.Rand) - 0.5) * 2
 }
 
 // When the exponential backoff has hit its cap, we need to jitter down, rather
 // than both high and low.
 func (b Backoff) jitterLow() float64 {
-	return -b.Jitter * rand.Float64()
+	return -b.Jitter * randFloat64(b.Rand)
 }
 
 // When the exponential backoff has hit its lower bound, we need to jitter up,
 // rather than both high and low.
 func (b Backoff) jitterHigh() float64 {
-	return b.Jitter * rand.Float64()
+	return b.Jitter * randFloat64(b.Rand)
 }
 
 // Reset resets the step-count on its receiver. It is *not* thread-safe.
@@ -87,12 +170,22 @@ func (b *Backoff) Reset() {
 	b.step = 0
 }
 
+// checkRange returns an error if MinBackoff > MaxBackoff.
+func (b *Backoff) checkRange() error {
+	if b.MinBackoff > b.MaxBackoff {
+		return fmt.Errorf("MinBackoff (%s) > MaxBackoff (%s)",
+			b.MinBackoff, b.MaxBackoff)
+	}
+	return nil
+}
+
 // BackoffN is a stateless method that uses the parameters in the receiver to
 // return a backoff interval appropriate for the Nth retry.
+// BackoffN panics if MinBackoff > MaxBackoff; Validate (which Retryable.Retry
+// calls up-front) reports this as an error instead.
 func (b *Backoff) BackoffN(n int) time.Duration {
-	if b.MinBackoff > b.MaxBackoff {
-		log.Panicf("MinBackoff (%s) > MaxBackoff(%s)",
-			b.MinBackoff, b.MaxBackoff)
+	if err := b.checkRange(); err != nil {
+		panic(err)
 	}
 
 	backoff := b.MinBackoff
@@ -105,6 +198,17 @@ func (b *Backoff) BackoffN(n int) time.Duration {
 		math.Max(float64(backoff.Nanoseconds())*expMul, 0),
 		float64(b.MaxBackoff.Nanoseconds()))
 	backoff = time.Duration(backoffNS) * time.Nanosecond
+	if backoff < b.MinBackoff {
+		backoff = b.MinBackoff
+	}
+
+	switch b.JitterMode {
+	case JitterFull:
+		return time.Duration(randFloat64(b.Rand)*float64(backoff.Nanoseconds())) * time.Nanosecond
+	case JitterEqual:
+		half := backoff / 2
+		return half + time.Duration(randFloat64(b.Rand)*float64((backoff-half).Nanoseconds()))*time.Nanosecond
+	}
 
 	jitter := b.jitter()
 	if backoff >= b.MaxBackoff {
@@ -135,3 +239,74 @@ func (b *Backoff) Next() time.Duration {
 	b.step++
 	return backoff
 }
+
+var _ BackoffStrategy = (*DecorrelatedJitter)(nil)
+
+// DecorrelatedJitter implements the "decorrelated jitter" backoff algorithm:
+// each interval is drawn uniformly from [MinBackoff, 3*previous interval) and
+// capped at MaxBackoff. Since every interval depends on the (random) previous
+// one, independent clients that start failing at the same time drift apart
+// much faster than with Backoff's symmetric jitter.
+type DecorrelatedJitter struct {
+	prev time.Duration
+	// MaxBackoff caps the generated intervals.
+	MaxBackoff time.Duration
+	// MinBackoff is both the first interval and the lower bound on all
+	// subsequent intervals. It should be > 0, otherwise every interval will
+	// be 0.
+	MinBackoff time.Duration
+	// Rand is the source of randomness. If nil, the global math/rand
+	// source is used. As with Backoff.Rand, it is shared between clones
+	// unless NewRand is set.
+	Rand RandSource
+	// NewRand, if non-nil, is called by Clone to create the clone's Rand
+	// (see Backoff.NewRand).
+	NewRand func() RandSource
+}
+
+// DefaultDecorrelatedJitter returns a DecorrelatedJitter instance with the
+// same bounds as DefaultBackoff.
+func DefaultDecorrelatedJitter() DecorrelatedJitter {
+	return DecorrelatedJitter{
+		MaxBackoff: time.Minute,
+		MinBackoff: time.Millisecond,
+	}
+}
+
+// Clone returns a cloned copy of a DecorrelatedJitter struct. If NewRand is
+// set, the clone gets a new Rand from it.
+func (d DecorrelatedJitter) Clone() DecorrelatedJitter {
+	return DecorrelatedJitter{
+		prev:       d.prev,
+		MaxBackoff: d.MaxBackoff,
+		MinBackoff: d.MinBackoff,
+		Rand:       cloneRand(d.Rand, d.NewRand),
+		NewRand:    d.NewRand,
+	}
+}
+
+// CloneStrategy implements BackoffStrategy, returning a pointer to a Clone of
+// the receiver.
+func (d DecorrelatedJitter) CloneStrategy() BackoffStrategy {
+	c := d.Clone()
+	return &c
+}
+
+// Reset resets the receiver to the start of its sequence. It is *not*
+// thread-safe.
+func (d *DecorrelatedJitter) Reset() {
+	d.prev = 0
+}
+
+// Next returns the next time interval to wait in the sequence.
+func (d *DecorrelatedJitter) Next() time.Duration {
+	prev := d.prev
+	if prev < d.MinBackoff {
+		prev = d.MinBackoff
+	}
+	lo := float64(d.MinBackoff.Nanoseconds())
+	hi := 3 * float64(prev.Nanoseconds())
+	nextNS := math.Min(lo+randFloat64(d.Rand)*(hi-lo), float64(d.MaxBackoff.Nanoseconds()))
+	d.prev = time.Duration(nextNS) * time.Nanosecond
+	return d.prev
+}
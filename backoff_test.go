@@ -16,6 +16,7 @@ package retry
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -103,3 +104,233 @@ func TestBackoffN(t *testing.T) {
 		t.Errorf("10th backoff too large: %s vs max with jitter: %s", backoff10, jitterMax)
 	}
 }
+
+func TestBackoffCloneStrategy(t *testing.T) {
+	b := &Backoff{
+		MaxBackoff: time.Minute,
+		MinBackoff: time.Second,
+		Jitter:     0,
+		ExpFactor:  2,
+	}
+	b.Next()
+	b.Next()
+
+	var s BackoffStrategy = b
+	c := s.CloneStrategy()
+	if d := c.Next(); d != 4*time.Second {
+		t.Errorf("clone did not preserve step: got %s; want %s", d, 4*time.Second)
+	}
+	c.Reset()
+	if d := c.Next(); d != time.Second {
+		t.Errorf("reset clone: got %s; want %s", d, time.Second)
+	}
+	if d := b.Next(); d != 4*time.Second {
+		t.Errorf("original affected by clone: got %s; want %s", d, 4*time.Second)
+	}
+}
+
+func TestDecorrelatedJitterNext(t *testing.T) {
+	d := DecorrelatedJitter{
+		MaxBackoff: time.Minute,
+		MinBackoff: time.Second,
+	}
+
+	prev := d.MinBackoff
+	sawMax := false
+	for i := 0; i < 1000; i++ {
+		n := d.Next()
+		if n < d.MinBackoff {
+			t.Errorf("d.Next() = %s, which is less than minimum: %s (i=%d)", n, d.MinBackoff, i)
+		}
+		if n > d.MaxBackoff {
+			t.Errorf("d.Next() = %s, which is greater than maximum: %s (i=%d)", n, d.MaxBackoff, i)
+		}
+		if upper := 3 * prev; n > upper {
+			t.Errorf("d.Next() = %s, which is greater than 3*previous: %s (i=%d)", n, upper, i)
+		}
+		if n == d.MaxBackoff {
+			sawMax = true
+		}
+		prev = n
+	}
+	if !sawMax {
+		t.Errorf("never reached MaxBackoff in 1000 iterations")
+	}
+
+	d.Reset()
+	if n := d.Next(); n > 3*d.MinBackoff {
+		t.Errorf("first interval after reset too large: %s", n)
+	}
+}
+
+// sampleJitterDistribution draws n samples from b.BackoffN(step) and returns
+// them normalized to the unjittered interval for that step, along with their
+// mean.
+func sampleJitterDistribution(b *Backoff, step, n int) ([]float64, float64) {
+	baseNS := math.Min(float64(b.MinBackoff.Nanoseconds())*math.Pow(b.ExpFactor, float64(step)),
+		float64(b.MaxBackoff.Nanoseconds()))
+	out := make([]float64, n)
+	sum := 0.
+	for i := range out {
+		out[i] = float64(b.BackoffN(step).Nanoseconds()) / baseNS
+		sum += out[i]
+	}
+	return out, sum / float64(n)
+}
+
+// checkUniform verifies that samples are uniformly distributed over [lo, hi)
+// by bucketing them into deciles.
+func checkUniform(t *testing.T, samples []float64, lo, hi float64) {
+	t.Helper()
+	const nBuckets = 10
+	buckets := [nBuckets]int{}
+	for _, s := range samples {
+		if s < lo || s >= hi {
+			t.Fatalf("sample %g outside [%g, %g)", s, lo, hi)
+		}
+		buckets[int((s-lo)/(hi-lo)*nBuckets)]++
+	}
+	expected := float64(len(samples)) / nBuckets
+	// The standard deviation of each bucket's count is roughly
+	// sqrt(expected) (~95 for 100k samples), so 6% is well over 6 sigma.
+	for i, c := range buckets {
+		if math.Abs(float64(c)-expected) > expected*0.06 {
+			t.Errorf("bucket %d has %d samples; expected ~%g", i, c, expected)
+		}
+	}
+}
+
+func TestBackoffJitterModes(t *testing.T) {
+	const samples = 100000
+	for _, tbl := range []struct {
+		name   string
+		mode   JitterMode
+		lo, hi float64
+		mean   float64
+	}{
+		{name: "proportional", mode: JitterProportional, lo: 0.9, hi: 1.1, mean: 1},
+		{name: "full", mode: JitterFull, lo: 0, hi: 1, mean: 0.5},
+		{name: "equal", mode: JitterEqual, lo: 0.5, hi: 1, mean: 0.75},
+	} {
+		tbl := tbl
+		t.Run(tbl.name, func(t *testing.T) {
+			t.Parallel()
+			b := Backoff{
+				MaxBackoff: time.Hour,
+				MinBackoff: time.Second,
+				Jitter:     0.1,
+				ExpFactor:  2,
+				JitterMode: tbl.mode,
+			}
+			// step 5 gives 32s, well away from both bounds
+			s, mean := sampleJitterDistribution(&b, 5, samples)
+			if math.Abs(mean-tbl.mean) > 0.01 {
+				t.Errorf("mean of normalized samples %g; expected %g", mean, tbl.mean)
+			}
+			checkUniform(t, s, tbl.lo, tbl.hi)
+		})
+	}
+}
+
+func TestBackoffJitterModesAtMax(t *testing.T) {
+	for _, mode := range []JitterMode{JitterFull, JitterEqual} {
+		b := Backoff{
+			MaxBackoff: time.Minute,
+			MinBackoff: time.Second,
+			ExpFactor:  2,
+			JitterMode: mode,
+		}
+		for i := 0; i < 1000; i++ {
+			if d := b.BackoffN(100); d > b.MaxBackoff {
+				t.Errorf("mode %d: BackoffN(100) = %s; greater than max %s", mode, d, b.MaxBackoff)
+			}
+		}
+	}
+}
+
+func TestBackoffSeededRand(t *testing.T) {
+	for _, mode := range []JitterMode{JitterProportional, JitterFull, JitterEqual} {
+		b1 := Backoff{
+			MaxBackoff: time.Minute,
+			MinBackoff: time.Second,
+			Jitter:     0.1,
+			ExpFactor:  1.2,
+			JitterMode: mode,
+			Rand:       rand.New(rand.NewSource(42)),
+		}
+		b2 := b1.Clone()
+		// Clones share the source, so give the clone its own one with the
+		// same seed.
+		if b2.Rand != b1.Rand {
+			t.Fatalf("mode %d: Clone did not preserve Rand", mode)
+		}
+		b2.Rand = rand.New(rand.NewSource(42))
+
+		for i := 0; i < 100; i++ {
+			if d1, d2 := b1.Next(), b2.Next(); d1 != d2 {
+				t.Fatalf("mode %d: identically seeded backoffs diverged at step %d: %s vs %s",
+					mode, i, d1, d2)
+			}
+		}
+	}
+}
+
+func TestDecorrelatedJitterSeededRand(t *testing.T) {
+	d1 := DefaultDecorrelatedJitter()
+	d1.Rand = rand.New(rand.NewSource(7))
+	d2 := d1.Clone()
+	if d2.Rand != d1.Rand {
+		t.Fatalf("Clone did not preserve Rand")
+	}
+	d2.Rand = rand.New(rand.NewSource(7))
+
+	for i := 0; i < 100; i++ {
+		if n1, n2 := d1.Next(), d2.Next(); n1 != n2 {
+			t.Fatalf("identically seeded strategies diverged at step %d: %s vs %s", i, n1, n2)
+		}
+	}
+}
+
+func TestBackoffNewRand(t *testing.T) {
+	b := DefaultBackoff()
+	b.NewRand = func() RandSource { return rand.New(rand.NewSource(42)) }
+	b1, b2 := b.Clone(), b.Clone()
+	if b1.Rand == nil || b1.Rand == b2.Rand {
+		t.Fatalf("clones did not get their own Rand from NewRand")
+	}
+	for i := 0; i < 100; i++ {
+		if d1, d2 := b1.Next(), b2.Next(); d1 != d2 {
+			t.Fatalf("identically seeded clones diverged at step %d: %s vs %s", i, d1, d2)
+		}
+	}
+
+	d := DefaultDecorrelatedJitter()
+	d.NewRand = func() RandSource { return rand.New(rand.NewSource(7)) }
+	d1, d2 := d.Clone(), d.Clone()
+	if d1.Rand == nil || d1.Rand == d2.Rand {
+		t.Fatalf("DecorrelatedJitter clones did not get their own Rand from NewRand")
+	}
+	for i := 0; i < 100; i++ {
+		if n1, n2 := d1.Next(), d2.Next(); n1 != n2 {
+			t.Fatalf("identically seeded clones diverged at step %d: %s vs %s", i, n1, n2)
+		}
+	}
+}
+
+func TestBackoffNInvalidRange(t *testing.T) {
+	b := Backoff{
+		MaxBackoff: time.Second,
+		MinBackoff: time.Minute,
+	}
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok {
+			t.Fatalf("expected BackoffN to panic with an error; got %v", r)
+		}
+		if err.Error() != "MinBackoff (1m0s) > MaxBackoff (1s)" {
+			t.Errorf("unexpected panic: %s", err)
+		}
+	}()
+	b.BackoffN(0)
+}
@@ -0,0 +1,91 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"sync"
+	"time"
+
+	clocks "github.com/vimeo/go-clocks"
+)
+
+// RetryBudgetConfig configures a RetryBudget.
+type RetryBudgetConfig struct {
+	// Ratio is the maximum number of retries, as a fraction of first
+	// attempts within Window (e.g. 0.2 allows one retry for every five
+	// first attempts).
+	Ratio float64
+	// MinRetriesPerSecond allows a baseline rate of retries regardless of
+	// Ratio, so low-traffic callers can still retry.
+	MinRetriesPerSecond float64
+	// Window is the period over which first attempts and retries are
+	// counted. Defaults to 10 seconds if <= 0.
+	Window time.Duration
+	// Clock provides the time (if nil, uses
+	// github.com/vimeo/go-clocks.DefaultClock())
+	Clock clocks.Clock
+}
+
+// RetryBudget limits retries to a fraction of recent first attempts, in the
+// style of gRPC and Finagle retry budgets. Sharing one RetryBudget across all
+// the Retryables calling a backend prevents retry storms when many callers
+// fail at once: once the budget is spent, failed attempts are not retried.
+// It is safe for concurrent use.
+type RetryBudget struct {
+	cfg   RetryBudgetConfig
+	clock clocks.Clock
+
+	mu sync.Mutex
+	// counts tracks first attempts (a) and retries (b)
+	counts slidingWindow
+}
+
+// NewRetryBudget returns a new RetryBudget.
+func NewRetryBudget(cfg RetryBudgetConfig) *RetryBudget {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	clock := cfg.Clock
+	if clock == nil {
+		clock = clocks.DefaultClock()
+	}
+	return &RetryBudget{
+		cfg:    cfg,
+		clock:  clock,
+		counts: newSlidingWindow(cfg.Window, windowBuckets),
+	}
+}
+
+// RecordAttempt records a first attempt, adding to the budget.
+func (b *RetryBudget) RecordAttempt() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counts.add(b.clock.Now(), 1, 0)
+}
+
+// TryRetry withdraws a retry from the budget, returning false (and
+// withdrawing nothing) if the budget is exhausted.
+func (b *RetryBudget) TryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	attempts, retries := b.counts.sums(now)
+	allowed := b.cfg.MinRetriesPerSecond*b.cfg.Window.Seconds() + b.cfg.Ratio*float64(attempts)
+	if float64(retries+1) > allowed {
+		return false
+	}
+	b.counts.add(now, 0, 1)
+	return true
+}
@@ -0,0 +1,163 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+func TestRetryBudget(t *testing.T) {
+	fc := fake.NewClock(time.Unix(1000, 0))
+	b := NewRetryBudget(RetryBudgetConfig{
+		Ratio:  0.5,
+		Window: 10 * time.Second,
+		Clock:  fc,
+	})
+	assert.False(t, b.TryRetry())
+	for i := 0; i < 4; i++ {
+		b.RecordAttempt()
+	}
+	assert.True(t, b.TryRetry())
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+
+	// Once the attempts age out, so does the budget...
+	fc.Advance(10 * time.Second)
+	b.RecordAttempt()
+	b.RecordAttempt()
+	// ... but so do the retries.
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+}
+
+func TestRetryBudgetMinRetries(t *testing.T) {
+	fc := fake.NewClock(time.Unix(1000, 0))
+	b := NewRetryBudget(RetryBudgetConfig{
+		MinRetriesPerSecond: 0.2,
+		Window:              10 * time.Second,
+		Clock:               fc,
+	})
+	assert.True(t, b.TryRetry())
+	assert.True(t, b.TryRetry())
+	assert.False(t, b.TryRetry())
+}
+
+func TestRetryBudgetConcurrent(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Unix(1000, 0))
+	b := NewRetryBudget(RetryBudgetConfig{
+		Ratio: 0.1,
+		Clock: fc,
+	})
+	for i := 0; i < 100; i++ {
+		b.RecordAttempt()
+	}
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	granted := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.TryRetry() {
+				mu.Lock()
+				defer mu.Unlock()
+				granted++
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, granted)
+}
+
+func TestRetryableSharedBudget(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	budget := NewRetryBudget(RetryBudgetConfig{
+		Ratio: 0.5,
+		Clock: fc,
+	})
+
+	newRetryable := func() *Retryable {
+		r := NewRetryable(10)
+		r.Clock = fc
+		r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+		r.Budget = budget
+		return r
+	}
+	r1, r2 := newRetryable(), newRetryable()
+
+	lastErr := errors.New("last")
+	// After a single first attempt, a ratio of 0.5 doesn't allow any
+	// retries, so we return the last error rather than sleeping.
+	q2 := 0
+	err := r2.Retry(context.Background(), func(ctx context.Context) error {
+		q2++
+		return lastErr
+	})
+	assert.Equal(t, lastErr, err)
+	assert.Equal(t, 1, q2)
+
+	// r1's first attempt brings the budget up to one retry.
+	c := make(chan struct{})
+	q1 := 0
+	go func() {
+		err := r1.Retry(context.Background(), func(ctx context.Context) error {
+			q1++
+			if q1 == 2 {
+				return lastErr
+			}
+			return errors.New("foo")
+		})
+		assert.Equal(t, lastErr, err)
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	<-c
+	assert.Equal(t, 2, q1)
+}
+
+func TestRetryableSingleStepBudget(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	budget := NewRetryBudget(RetryBudgetConfig{
+		Ratio: 1,
+		Clock: fc,
+	})
+	r := NewRetryable(1)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+	r.Budget = budget
+
+	for i := 0; i < 3; i++ {
+		err := r.Retry(context.Background(), func(ctx context.Context) error {
+			return errors.New("foo")
+		})
+		// Running out of MaxSteps, not out of budget.
+		assert.IsType(t, &Errors{}, err)
+	}
+	// A Retryable with a single step never retries, so it never draws on
+	// the budget.
+	attempts, retries := budget.counts.sums(fc.Now())
+	assert.EqualValues(t, 3, attempts)
+	assert.Zero(t, retries)
+}
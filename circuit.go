@@ -0,0 +1,266 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	clocks "github.com/vimeo/go-clocks"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState uint8
+
+const (
+	// CircuitClosed allows all calls through, while tracking the failure
+	// rate.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all calls until the cooldown expires.
+	CircuitOpen
+	// CircuitHalfOpen allows a limited number of probe calls through to
+	// decide whether to close or re-open.
+	CircuitHalfOpen
+)
+
+// String implements fmt.Stringer
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", uint8(s))
+	}
+}
+
+// ErrCircuitOpen is returned by CircuitBreaker.Allow when a call is
+// rejected, and wrapped by the *CircuitOpenError Retryable.Retry returns in
+// that case.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned by Retryable.Retry when its CircuitBreaker
+// rejects an attempt. errors.Is(err, ErrCircuitOpen) is true for it, as it is
+// for the errors from the attempts made before that.
+type CircuitOpenError struct {
+	// Errors holds the errors from any attempts made before the circuit
+	// breaker rejected one.
+	*Errors
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v", e)
+}
+
+// Format implements fmt.Formatter, prefixing the formatted Errors (see
+// Errors.Format) with ErrCircuitOpen, if any attempts were made.
+func (e *CircuitOpenError) Format(s fmt.State, verb rune) {
+	if e.Errors == nil || e.Errors.count() == 0 {
+		formatMessage(s, verb, e, ErrCircuitOpen.Error())
+		return
+	}
+	formatWrapped(s, verb, e, fmt.Sprintf("%s after %d failed attempts",
+		ErrCircuitOpen, e.Errors.count()), e.Errors)
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// Window is the period over which the failure rate is measured.
+	Window time.Duration
+	// FailureRate is the fraction of calls in Window that must fail for
+	// the circuit to open. It should be in (0, 1]; values <= 0 are
+	// treated as 0.5.
+	FailureRate float64
+	// MinCalls is the minimum number of calls in Window before the
+	// failure rate is considered. Values < 1 are treated as 10, so that a
+	// Retryable using the CircuitBreaker still gets to retry an occasional
+	// failure.
+	MinCalls int
+	// Cooldown is how long the circuit stays open before allowing probe
+	// calls through.
+	Cooldown time.Duration
+	// Probes is the number of probe calls allowed while half-open; the
+	// circuit closes once that many succeed, and re-opens as soon as one
+	// fails. Values < 1 are treated as 1.
+	Probes int
+	// IsFailure classifies the outcome of a call; if nil, any non-nil
+	// error is a failure.
+	IsFailure func(error) bool
+	// OnStateChange, if non-nil, is called on every state transition. It
+	// is called with the CircuitBreaker's lock held, so it must not call
+	// back into the CircuitBreaker.
+	OnStateChange func(from, to CircuitState)
+	// Clock provides the time (if nil, uses
+	// github.com/vimeo/go-clocks.DefaultClock())
+	Clock clocks.Clock
+}
+
+// windowBuckets is the number of buckets used to approximate sliding windows.
+const windowBuckets = 10
+
+// CircuitBreaker tracks the failure rate of calls to a dependency, and stops
+// calls through for a cooldown period once it gets too high, so retrying
+// against a dead dependency doesn't multiply its load.
+// It is safe for concurrent use, and may be shared by multiple Retryables
+// calling the same dependency.
+type CircuitBreaker struct {
+	cfg   CircuitBreakerConfig
+	clock clocks.Clock
+
+	mu       sync.Mutex
+	state    CircuitState
+	openedAt time.Time
+	// calls tracks failures (a) and total calls (b) while closed.
+	calls slidingWindow
+	// probes started and succeeded while half-open
+	probesStarted   int
+	probesSucceeded int
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.Probes < 1 {
+		cfg.Probes = 1
+	}
+	if cfg.FailureRate <= 0 {
+		cfg.FailureRate = 0.5
+	}
+	if cfg.MinCalls < 1 {
+		cfg.MinCalls = 10
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(err error) bool { return err != nil }
+	}
+	clock := cfg.Clock
+	if clock == nil {
+		clock = clocks.DefaultClock()
+	}
+	return &CircuitBreaker{
+		cfg:   cfg,
+		clock: clock,
+		state: CircuitClosed,
+		calls: newSlidingWindow(cfg.Window, windowBuckets),
+	}
+}
+
+// setState transitions to "to"; callers must hold c.mu.
+func (c *CircuitBreaker) setState(to CircuitState, now time.Time) {
+	from := c.state
+	if from == to {
+		return
+	}
+	c.state = to
+	switch to {
+	case CircuitOpen:
+		c.openedAt = now
+	case CircuitHalfOpen:
+		c.probesStarted = 0
+		c.probesSucceeded = 0
+	case CircuitClosed:
+		c.calls.reset()
+	}
+	if c.cfg.OnStateChange != nil {
+		c.cfg.OnStateChange(from, to)
+	}
+}
+
+// updateLocked moves an open circuit to half-open once its cooldown has
+// expired; callers must hold c.mu.
+func (c *CircuitBreaker) updateLocked(now time.Time) {
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= c.cfg.Cooldown {
+		c.setState(CircuitHalfOpen, now)
+	}
+}
+
+// State returns the current state.
+func (c *CircuitBreaker) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updateLocked(c.clock.Now())
+	return c.state
+}
+
+// Allow returns ErrCircuitOpen if a call should not be made now. Otherwise,
+// the caller must report the call's result with Record.
+func (c *CircuitBreaker) Allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updateLocked(c.clock.Now())
+	switch c.state {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if c.probesStarted >= c.cfg.Probes {
+			return ErrCircuitOpen
+		}
+		c.probesStarted++
+	}
+	return nil
+}
+
+// allowsAt returns whether Allow would allow a call at the given (future)
+// time, as far as can be predicted now.
+func (c *CircuitBreaker) allowsAt(at time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch c.state {
+	case CircuitOpen:
+		return at.Sub(c.openedAt) >= c.cfg.Cooldown
+	case CircuitHalfOpen:
+		return c.probesStarted < c.cfg.Probes
+	}
+	return true
+}
+
+// Record reports the result of a call allowed by Allow.
+func (c *CircuitBreaker) Record(err error) {
+	failed := c.cfg.IsFailure(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	c.updateLocked(now)
+	switch c.state {
+	case CircuitClosed:
+		fail := uint64(0)
+		if failed {
+			fail = 1
+		}
+		c.calls.add(now, fail, 1)
+		failures, total := c.calls.sums(now)
+		if failures > 0 && total >= uint64(c.cfg.MinCalls) &&
+			float64(failures)/float64(total) >= c.cfg.FailureRate {
+			c.setState(CircuitOpen, now)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			c.setState(CircuitOpen, now)
+			return
+		}
+		c.probesSucceeded++
+		if c.probesSucceeded >= c.cfg.Probes {
+			c.setState(CircuitClosed, now)
+		}
+	case CircuitOpen:
+		// A straggler that was allowed through before the circuit
+		// opened; it doesn't tell us anything new.
+	}
+}
@@ -0,0 +1,241 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+type stateChange struct {
+	from, to CircuitState
+}
+
+func newTestBreaker(fc *fake.Clock, changes *[]stateChange) *CircuitBreaker {
+	return NewCircuitBreaker(CircuitBreakerConfig{
+		Window:      time.Minute,
+		FailureRate: 0.5,
+		MinCalls:    4,
+		Cooldown:    30 * time.Second,
+		Probes:      2,
+		Clock:       fc,
+		OnStateChange: func(from, to CircuitState) {
+			*changes = append(*changes, stateChange{from: from, to: to})
+		},
+	})
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	fc := fake.NewClock(time.Unix(1000, 0))
+	changes := []stateChange{}
+	cb := newTestBreaker(fc, &changes)
+	fail := errors.New("fail")
+
+	// Below MinCalls nothing happens, even with 100% failures.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, cb.Allow())
+		cb.Record(fail)
+	}
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	// Old failures age out of the window.
+	fc.Advance(2 * time.Minute)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, cb.Allow())
+		cb.Record(nil)
+	}
+	require.NoError(t, cb.Allow())
+	cb.Record(fail)
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	// 2/5 failures: still closed; 3/6: open.
+	require.NoError(t, cb.Allow())
+	cb.Record(fail)
+	assert.Equal(t, CircuitClosed, cb.State())
+	require.NoError(t, cb.Allow())
+	cb.Record(fail)
+	assert.Equal(t, CircuitOpen, cb.State())
+	assert.Equal(t, ErrCircuitOpen, cb.Allow())
+
+	// After the cooldown, two probes are let through.
+	fc.Advance(30 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	require.NoError(t, cb.Allow())
+	require.NoError(t, cb.Allow())
+	assert.Equal(t, ErrCircuitOpen, cb.Allow())
+
+	// A failed probe re-opens it.
+	cb.Record(nil)
+	cb.Record(fail)
+	assert.Equal(t, CircuitOpen, cb.State())
+
+	// Two successful probes close it.
+	fc.Advance(30 * time.Second)
+	require.NoError(t, cb.Allow())
+	cb.Record(nil)
+	assert.Equal(t, CircuitHalfOpen, cb.State())
+	require.NoError(t, cb.Allow())
+	cb.Record(nil)
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	// The window is cleared on closing.
+	require.NoError(t, cb.Allow())
+	cb.Record(fail)
+	assert.Equal(t, CircuitClosed, cb.State())
+
+	assert.Equal(t, []stateChange{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}, changes)
+}
+
+func TestCircuitBreakerIsFailure(t *testing.T) {
+	fc := fake.NewClock(time.Unix(1000, 0))
+	notFound := errors.New("not found")
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		Window:   time.Minute,
+		MinCalls: 1,
+		Cooldown: time.Second,
+		Clock:    fc,
+		IsFailure: func(err error) bool {
+			return err != nil && !errors.Is(err, notFound)
+		},
+	})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, cb.Allow())
+		cb.Record(notFound)
+	}
+	assert.Equal(t, CircuitClosed, cb.State())
+}
+
+func TestRetryableCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	changes := []stateChange{}
+	cb := newTestBreaker(fc, &changes)
+
+	r := NewRetryable(10)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+	r.Breaker = cb
+
+	fooErr := errors.New("foo")
+	c := make(chan struct{})
+	q := 0
+	go func() {
+		err := r.Retry(context.Background(), func(ctx context.Context) error {
+			q++
+			return fooErr
+		})
+		require.True(t, errors.Is(err, ErrCircuitOpen))
+		theErr := &CircuitOpenError{}
+		require.True(t, errors.As(err, &theErr))
+		assert.Len(t, theErr.Errs, 4)
+		assert.True(t, errors.Is(err, fooErr))
+		close(c)
+	}()
+	for i := 0; i < 3; i++ {
+		fc.AwaitSleepers(1)
+		fc.Advance(time.Second)
+	}
+	<-c
+	// The fourth failure opened the circuit for longer than the backoff,
+	// so Retry returned without sleeping, and the fifth attempt was never
+	// made.
+	assert.Equal(t, 4, q)
+	assert.Zero(t, fc.NumSleepers())
+
+	err := r.Retry(context.Background(), func(ctx context.Context) error {
+		t.Error("unexpected call while the circuit is open")
+		return nil
+	})
+	assert.EqualError(t, err, "circuit breaker is open")
+}
+
+func TestRetryableCircuitBreakerLastAttempt(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	changes := []stateChange{}
+	cb := newTestBreaker(fc, &changes)
+
+	r := NewRetryable(4)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+	r.Breaker = cb
+
+	c := make(chan error)
+	go func() {
+		c <- r.Retry(context.Background(), func(ctx context.Context) error {
+			return errors.New("foo")
+		})
+	}()
+	// Including the backoff after the final attempt.
+	for i := 0; i < 4; i++ {
+		fc.AwaitSleepers(1)
+		fc.Advance(time.Second)
+	}
+	err := <-c
+	// The final failure opened the circuit, but there was no retry for it
+	// to reject: Retry ran out of attempts.
+	assert.Equal(t, CircuitOpen, cb.State())
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Len(t, theErr.Errs, 4)
+	assert.Equal(t, OutcomeExhausted, outcomeOf(err))
+}
+
+func TestCircuitBreakerDefaultMinCalls(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(5)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+	r.Breaker = NewCircuitBreaker(CircuitBreakerConfig{
+		Window:   time.Minute,
+		Cooldown: time.Minute,
+		Clock:    fc,
+	})
+
+	c := make(chan struct{})
+	calls := 0
+	go func() {
+		err := r.Retry(context.Background(), func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("foo")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		close(c)
+	}()
+	for i := 0; i < 2; i++ {
+		fc.AwaitSleepers(1)
+		fc.Advance(time.Second)
+	}
+	<-c
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, CircuitClosed, r.Breaker.State())
+}
@@ -0,0 +1,95 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	clocks "github.com/vimeo/go-clocks"
+)
+
+// clockTimeoutCtx is a context that expires with context.DeadlineExceeded
+// after a timeout measured by a clocks.Clock, so fake clocks can drive it.
+type clockTimeoutCtx struct {
+	parent   context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// withClockTimeout is the equivalent of context.WithTimeout, but with the
+// timeout measured by clock.
+func withClockTimeout(parent context.Context, clock clocks.Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	c := &clockTimeoutCtx{
+		parent:   parent,
+		deadline: clock.Now().Add(d),
+		done:     make(chan struct{}),
+	}
+	sleepCtx, stopSleep := context.WithCancel(parent)
+	go func() {
+		if clock.SleepFor(sleepCtx, d) {
+			c.cancel(context.DeadlineExceeded)
+			return
+		}
+		// Either the parent expired, or we were cancelled (in which case
+		// c.err is already set).
+		if err := parent.Err(); err != nil {
+			c.cancel(err)
+		}
+	}()
+	return c, func() {
+		c.cancel(context.Canceled)
+		stopSleep()
+	}
+}
+
+func (c *clockTimeoutCtx) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
+
+// Deadline implements context.Context
+func (c *clockTimeoutCtx) Deadline() (time.Time, bool) {
+	if dl, ok := c.parent.Deadline(); ok && dl.Before(c.deadline) {
+		return dl, true
+	}
+	return c.deadline, true
+}
+
+// Done implements context.Context
+func (c *clockTimeoutCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err implements context.Context
+func (c *clockTimeoutCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Value implements context.Context
+func (c *clockTimeoutCtx) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
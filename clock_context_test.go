@@ -0,0 +1,76 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+func TestClockTimeoutCtxExpires(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	ctx, cancel := withClockTimeout(context.Background(), fc, time.Minute)
+	defer cancel()
+
+	dl, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, fc.Now().Add(time.Minute), dl)
+	assert.NoError(t, ctx.Err())
+
+	// derived contexts should see the same error
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Minute)
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	<-child.Done()
+	assert.Equal(t, context.DeadlineExceeded, child.Err())
+}
+
+func TestClockTimeoutCtxCancel(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	ctx, cancel := withClockTimeout(context.Background(), fc, time.Minute)
+	fc.AwaitSleepers(1)
+	cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+	// the timer goroutine should give up its sleep
+	fc.AwaitSleepAborts(1)
+}
+
+func TestClockTimeoutCtxParent(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	parentDL := time.Now().Add(time.Second)
+	parent, parentCancel := context.WithDeadline(context.Background(), parentDL)
+	ctx, cancel := withClockTimeout(parent, fc, time.Hour)
+	defer cancel()
+
+	dl, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, parentDL, dl)
+
+	parentCancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
@@ -0,0 +1,348 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	_ encoding.TextMarshaler   = Backoff{}
+	_ encoding.TextUnmarshaler = (*Backoff)(nil)
+	_ json.Marshaler           = Backoff{}
+	_ json.Unmarshaler         = (*Backoff)(nil)
+	_ json.Marshaler           = RetryableConfig{}
+	_ json.Unmarshaler         = (*RetryableConfig)(nil)
+)
+
+// String returns the name of the jitter mode, as accepted by UnmarshalText.
+func (m JitterMode) String() string {
+	switch m {
+	case JitterProportional:
+		return "proportional"
+	case JitterFull:
+		return "full"
+	case JitterEqual:
+		return "equal"
+	default:
+		return "JitterMode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (m JitterMode) MarshalText() ([]byte, error) {
+	switch m {
+	case JitterProportional, JitterFull, JitterEqual:
+		return []byte(m.String()), nil
+	default:
+		return nil, fmt.Errorf("unknown jitter mode %d", m)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting the names
+// returned by String.
+func (m *JitterMode) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "proportional", "":
+		*m = JitterProportional
+	case "full":
+		*m = JitterFull
+	case "equal":
+		*m = JitterEqual
+	default:
+		return fmt.Errorf("unknown jitter mode %q", text)
+	}
+	return nil
+}
+
+// duration marshals to and from the format of time.Duration.String (e.g.
+// "250ms"), rather than a count of nanoseconds.
+type duration time.Duration
+
+func (d duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+// parseFloat parses a finite float64.
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%s is not a finite number", s)
+	}
+	return f, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// String returns a compact, comma-separated list of the receiver's
+// parameters, such as "min=10ms,max=30s,factor=2,jitter=0.1", which
+// UnmarshalText (and Set) parse. The jitter mode is included (as e.g.
+// "mode=full") unless it is JitterProportional.
+func (b Backoff) String() string {
+	s := fmt.Sprintf("min=%s,max=%s,factor=%s,jitter=%s",
+		b.MinBackoff, b.MaxBackoff, formatFloat(b.ExpFactor), formatFloat(b.Jitter))
+	if b.JitterMode != JitterProportional {
+		s += ",mode=" + b.JitterMode.String()
+	}
+	return s
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the format of
+// String.
+func (b Backoff) MarshalText() ([]byte, error) {
+	if _, err := b.JitterMode.MarshalText(); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the format
+// generated by MarshalText. Parameters may appear in any order; those that
+// are omitted are left unchanged. It returns an error, leaving the receiver
+// unchanged, if the result would be invalid.
+func (b *Backoff) UnmarshalText(text []byte) error {
+	out := b.Clone()
+	if err := parseParams(string(text), func(key, val string) (bool, error) {
+		return out.setParam(key, val)
+	}); err != nil {
+		return err
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*b = out
+	return nil
+}
+
+// setParam sets the parameter named by key (as generated by MarshalText) to
+// val, returning false if key is not the name of a parameter.
+func (b *Backoff) setParam(key, val string) (bool, error) {
+	var err error
+	switch key {
+	case "min":
+		b.MinBackoff, err = time.ParseDuration(val)
+	case "max":
+		b.MaxBackoff, err = time.ParseDuration(val)
+	case "factor":
+		b.ExpFactor, err = parseFloat(val)
+	case "jitter":
+		b.Jitter, err = parseFloat(val)
+	case "mode":
+		err = b.JitterMode.UnmarshalText([]byte(val))
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// parseParams splits s into comma-separated key=value pairs, calling set for
+// each one. set returns false if key is unknown.
+func parseParams(s string, set func(key, val string) (bool, error)) error {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	for _, param := range strings.Split(s, ",") {
+		key, val, ok := cut(strings.TrimSpace(param), "=")
+		if !ok {
+			return fmt.Errorf("invalid parameter %q: expected key=value", param)
+		}
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		known, err := set(key, val)
+		if !known {
+			return fmt.Errorf("unknown parameter %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// cut is strings.Cut, which is only available with go1.18 and later.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// backoffJSON is the JSON representation of a Backoff.
+type backoffJSON struct {
+	MinBackoff duration   `json:"min_backoff"`
+	MaxBackoff duration   `json:"max_backoff"`
+	Jitter     float64    `json:"jitter"`
+	ExpFactor  float64    `json:"exp_factor"`
+	JitterMode JitterMode `json:"jitter_mode"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding durations in the format of
+// time.Duration.String (e.g. "250ms") and the jitter mode by name.
+func (b Backoff) MarshalJSON() ([]byte, error) {
+	return json.Marshal(backoffJSON{
+		MinBackoff: duration(b.MinBackoff),
+		MaxBackoff: duration(b.MaxBackoff),
+		Jitter:     b.Jitter,
+		ExpFactor:  b.ExpFactor,
+		JitterMode: b.JitterMode,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the format generated by
+// MarshalJSON. Fields that are omitted are left unchanged. It returns an
+// error, leaving the receiver unchanged, if the result would be invalid.
+func (b *Backoff) UnmarshalJSON(data []byte) error {
+	aux := backoffJSON{
+		MinBackoff: duration(b.MinBackoff),
+		MaxBackoff: duration(b.MaxBackoff),
+		Jitter:     b.Jitter,
+		ExpFactor:  b.ExpFactor,
+		JitterMode: b.JitterMode,
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	out := b.Clone()
+	out.MinBackoff = time.Duration(aux.MinBackoff)
+	out.MaxBackoff = time.Duration(aux.MaxBackoff)
+	out.Jitter = aux.Jitter
+	out.ExpFactor = aux.ExpFactor
+	out.JitterMode = aux.JitterMode
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*b = out
+	return nil
+}
+
+// RetryableConfig holds the tunable parameters of a Retryable, so they can be
+// loaded from configuration files. It excludes the fields holding functions
+// and other run-time dependencies, such as ShouldRetry, Clock and the hooks.
+type RetryableConfig struct {
+	// Backoff is copied to Retryable.B.
+	Backoff Backoff
+	// MaxSteps is copied to Retryable.MaxSteps.
+	MaxSteps int32
+	// MaxElapsed is copied to Retryable.MaxElapsed.
+	MaxElapsed time.Duration
+	// AttemptTimeout is copied to Retryable.AttemptTimeout.
+	AttemptTimeout time.Duration
+	// ErrorRetention is copied to Retryable.ErrorRetention.
+	ErrorRetention ErrorRetention
+}
+
+// Config returns the receiver's tunable parameters.
+func (r *Retryable) Config() RetryableConfig {
+	return RetryableConfig{
+		Backoff:        r.B.Clone(),
+		MaxSteps:       r.MaxSteps,
+		MaxElapsed:     r.MaxElapsed,
+		AttemptTimeout: r.AttemptTimeout,
+		ErrorRetention: r.ErrorRetention,
+	}
+}
+
+// Apply sets the fields of r corresponding to the receiver's fields, leaving
+// the others unchanged.
+func (c RetryableConfig) Apply(r *Retryable) {
+	r.B = c.Backoff.Clone()
+	r.MaxSteps = c.MaxSteps
+	r.MaxElapsed = c.MaxElapsed
+	r.AttemptTimeout = c.AttemptTimeout
+	r.ErrorRetention = c.ErrorRetention
+}
+
+// Validate returns a *ValidationError reporting every problem with the
+// receiver's parameters, as Retryable.Validate would after Apply.
+func (c RetryableConfig) Validate() error {
+	r := Retryable{}
+	c.Apply(&r)
+	return r.Validate()
+}
+
+// retryableConfigJSON is the JSON representation of a RetryableConfig.
+type retryableConfigJSON struct {
+	Backoff        Backoff         `json:"backoff"`
+	MaxSteps       int32           `json:"max_steps"`
+	MaxElapsed     duration        `json:"max_elapsed,omitempty"`
+	AttemptTimeout duration        `json:"attempt_timeout,omitempty"`
+	ErrorRetention *ErrorRetention `json:"error_retention,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding durations in the format of
+// time.Duration.String (e.g. "250ms"). ErrorRetention is omitted if it is
+// the zero value.
+func (c RetryableConfig) MarshalJSON() ([]byte, error) {
+	aux := retryableConfigJSON{
+		Backoff:        c.Backoff,
+		MaxSteps:       c.MaxSteps,
+		MaxElapsed:     duration(c.MaxElapsed),
+		AttemptTimeout: duration(c.AttemptTimeout),
+	}
+	if c.ErrorRetention != (ErrorRetention{}) {
+		aux.ErrorRetention = &c.ErrorRetention
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, decoding the format generated by
+// MarshalJSON. Fields that are omitted are left unchanged. It returns an
+// error, leaving the receiver unchanged, if the result would be invalid.
+func (c *RetryableConfig) UnmarshalJSON(data []byte) error {
+	retention := c.ErrorRetention
+	aux := retryableConfigJSON{
+		Backoff:        c.Backoff,
+		MaxSteps:       c.MaxSteps,
+		MaxElapsed:     duration(c.MaxElapsed),
+		AttemptTimeout: duration(c.AttemptTimeout),
+		ErrorRetention: &retention,
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	out := RetryableConfig{
+		Backoff:        aux.Backoff,
+		MaxSteps:       aux.MaxSteps,
+		MaxElapsed:     time.Duration(aux.MaxElapsed),
+		AttemptTimeout: time.Duration(aux.AttemptTimeout),
+	}
+	if aux.ErrorRetention != nil {
+		out.ErrorRetention = *aux.ErrorRetention
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*c = out
+	return nil
+}
@@ -0,0 +1,150 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffText(t *testing.T) {
+	t.Parallel()
+	b := Backoff{
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+		ExpFactor:  2,
+		Jitter:     0.1,
+	}
+	text, err := b.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "min=10ms,max=30s,factor=2,jitter=0.1", string(text))
+
+	b.JitterMode = JitterFull
+	text, err = b.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "min=10ms,max=30s,factor=2,jitter=0.1,mode=full", string(text))
+
+	var got Backoff
+	require.NoError(t, got.UnmarshalText(text))
+	assert.Equal(t, b, got)
+
+	// Omitted parameters are left alone, and whitespace is ignored.
+	got = DefaultBackoff()
+	require.NoError(t, got.UnmarshalText([]byte(" jitter = 0.5, min=2ms ")))
+	want := DefaultBackoff()
+	want.Jitter = 0.5
+	want.MinBackoff = 2 * time.Millisecond
+	assert.Equal(t, want, got)
+}
+
+func TestBackoffTextErrors(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		text string
+		err  string
+	}{
+		{text: "min=1s,max=1ms", err: "invalid backoff: MinBackoff (1s) > MaxBackoff (1ms)"},
+		{text: "min=10", err: "invalid value for min: time: missing unit in duration \"10\""},
+		{text: "factor=NaN", err: "invalid value for factor: NaN is not a finite number"},
+		{text: "mode=half", err: "invalid value for mode: unknown jitter mode \"half\""},
+		{text: "steps=3", err: "unknown parameter \"steps\""},
+		{text: "min", err: "invalid parameter \"min\": expected key=value"},
+	} {
+		b := DefaultBackoff()
+		assert.EqualError(t, b.UnmarshalText([]byte(tc.text)), tc.err, tc.text)
+		assert.Equal(t, DefaultBackoff(), b, tc.text)
+	}
+}
+
+func TestBackoffJSON(t *testing.T) {
+	t.Parallel()
+	b := DefaultBackoff()
+	b.JitterMode = JitterEqual
+	data, err := json.Marshal(b)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"min_backoff": "1ms",
+		"max_backoff": "1m0s",
+		"jitter": 0.1,
+		"exp_factor": 1.2,
+		"jitter_mode": "equal"
+	}`, string(data))
+
+	var got Backoff
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, b, got)
+
+	got = DefaultBackoff()
+	require.NoError(t, json.Unmarshal([]byte(`{"max_backoff": "250ms"}`), &got))
+	assert.Equal(t, 250*time.Millisecond, got.MaxBackoff)
+	assert.Equal(t, time.Millisecond, got.MinBackoff)
+
+	got = DefaultBackoff()
+	assert.EqualError(t, json.Unmarshal([]byte(`{"min_backoff": "2m"}`), &got),
+		"invalid backoff: MinBackoff (2m0s) > MaxBackoff (1m0s)")
+	assert.Equal(t, DefaultBackoff(), got)
+	assert.Error(t, json.Unmarshal([]byte(`{"min_backoff": 1000}`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`{"jitter_mode": "none"}`), &got))
+}
+
+func TestRetryableConfigJSON(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(5)
+	r.MaxElapsed = time.Minute
+	r.ShouldRetry = func(error) bool { return false }
+	data, err := json.Marshal(r.Config())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"backoff": {
+			"min_backoff": "1ms",
+			"max_backoff": "1m0s",
+			"jitter": 0.1,
+			"exp_factor": 1.2,
+			"jitter_mode": "proportional"
+		},
+		"max_steps": 5,
+		"max_elapsed": "1m0s"
+	}`, string(data))
+
+	cfg := r.Config()
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"backoff": {"max_backoff": "10s"},
+		"max_steps": 8,
+		"attempt_timeout": "2s",
+		"error_retention": {"first": 2, "last": 3}
+	}`), &cfg))
+	cfg.Apply(r)
+	assert.Equal(t, ErrorRetention{First: 2, Last: 3}, r.ErrorRetention)
+	assert.Equal(t, 10*time.Second, r.B.MaxBackoff)
+	assert.Equal(t, time.Millisecond, r.B.MinBackoff)
+	assert.Equal(t, int32(8), r.MaxSteps)
+	assert.Equal(t, time.Minute, r.MaxElapsed)
+	assert.Equal(t, 2*time.Second, r.AttemptTimeout)
+	assert.NotNil(t, r.ShouldRetry)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"backoff": {"min_backoff": "1h"}}`), &cfg))
+
+	data, err = json.Marshal(r.Config())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"error_retention":{"first":2,"last":3}`)
+	// Omitted fields of error_retention are left alone too.
+	require.NoError(t, json.Unmarshal([]byte(`{"error_retention": {"last": 5}}`), &cfg))
+	assert.Equal(t, ErrorRetention{First: 2, Last: 5}, cfg.ErrorRetention)
+	assert.Error(t, json.Unmarshal([]byte(`{"error_retention": {"first": -1}}`), &cfg))
+}
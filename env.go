@@ -0,0 +1,100 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultEnvPrefix is the prefix used by FromEnv if none is given.
+const DefaultEnvPrefix = "RETRY"
+
+// FromEnv returns a copy of base with its tunable parameters overridden by
+// any of the following environment variables that are set and non-empty:
+//
+//	<prefix>_MIN_BACKOFF      B.MinBackoff (e.g. "250ms")
+//	<prefix>_MAX_BACKOFF      B.MaxBackoff
+//	<prefix>_JITTER           B.Jitter
+//	<prefix>_EXP_FACTOR       B.ExpFactor
+//	<prefix>_JITTER_MODE      B.JitterMode ("proportional", "full" or "equal")
+//	<prefix>_MAX_STEPS        MaxSteps
+//	<prefix>_MAX_ELAPSED      MaxElapsed
+//	<prefix>_ATTEMPT_TIMEOUT  AttemptTimeout
+//
+// The prefix defaults to DefaultEnvPrefix if empty. Values are parsed as by
+// Backoff.UnmarshalText. FromEnv returns a *ValidationError naming every
+// variable that couldn't be parsed, or reporting every problem with the
+// resulting parameters (see RetryableConfig.Validate), in which case base is
+// returned unchanged.
+func FromEnv(prefix string, base Retryable) (Retryable, error) {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	cfg := base.Config()
+	v := validator{}
+	for _, env := range []struct {
+		suffix string
+		set    func(val string) error
+	}{
+		{"_MIN_BACKOFF", backoffParamSetter(&cfg.Backoff, "min")},
+		{"_MAX_BACKOFF", backoffParamSetter(&cfg.Backoff, "max")},
+		{"_JITTER", backoffParamSetter(&cfg.Backoff, "jitter")},
+		{"_EXP_FACTOR", backoffParamSetter(&cfg.Backoff, "factor")},
+		{"_JITTER_MODE", backoffParamSetter(&cfg.Backoff, "mode")},
+		{"_MAX_STEPS", func(val string) error {
+			steps, err := strconv.ParseInt(val, 10, 32)
+			cfg.MaxSteps = int32(steps)
+			return err
+		}},
+		{"_MAX_ELAPSED", durationSetter(&cfg.MaxElapsed)},
+		{"_ATTEMPT_TIMEOUT", durationSetter(&cfg.AttemptTimeout)},
+	} {
+		name := prefix + env.suffix
+		val := os.Getenv(name)
+		if val == "" {
+			continue
+		}
+		if err := env.set(val); err != nil {
+			v.check(false, "%s: %w", name, err)
+		}
+	}
+	if err := v.err("environment"); err != nil {
+		return base, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return base, err
+	}
+	cfg.Apply(&base)
+	return base, nil
+}
+
+// backoffParamSetter returns a function setting b's parameter named key (as
+// in Backoff.String).
+func backoffParamSetter(b *Backoff, key string) func(string) error {
+	return func(val string) error {
+		_, err := b.setParam(key, val)
+		return err
+	}
+}
+
+// durationSetter returns a function setting d to a parsed duration.
+func durationSetter(d *time.Duration) func(string) error {
+	return func(val string) (err error) {
+		*d, err = time.ParseDuration(val)
+		return err
+	}
+}
@@ -0,0 +1,86 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("DB_MIN_BACKOFF", "10ms")
+	t.Setenv("DB_MAX_BACKOFF", "30s")
+	t.Setenv("DB_JITTER", "0.2")
+	t.Setenv("DB_EXP_FACTOR", "2")
+	t.Setenv("DB_JITTER_MODE", "full")
+	t.Setenv("DB_MAX_STEPS", "8")
+	t.Setenv("DB_MAX_ELAPSED", "")
+	t.Setenv("DB_ATTEMPT_TIMEOUT", "2s")
+
+	base := *NewRetryable(3)
+	base.MaxElapsed = time.Minute
+	base.ShouldRetry = func(error) bool { return false }
+	r, err := FromEnv("DB", base)
+	require.NoError(t, err)
+	assert.Equal(t, Backoff{
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+		Jitter:     0.2,
+		ExpFactor:  2,
+		JitterMode: JitterFull,
+	}, r.B)
+	assert.Equal(t, int32(8), r.MaxSteps)
+	// Empty variables are ignored.
+	assert.Equal(t, time.Minute, r.MaxElapsed)
+	assert.Equal(t, 2*time.Second, r.AttemptTimeout)
+	assert.NotNil(t, r.ShouldRetry)
+	assert.NotNil(t, r.Clock)
+	// base is a copy, so isn't modified.
+	assert.Equal(t, DefaultBackoff(), base.B)
+}
+
+func TestFromEnvDefaultPrefix(t *testing.T) {
+	t.Setenv("RETRY_MAX_STEPS", "12")
+	r, err := FromEnv("", *NewRetryable(3))
+	require.NoError(t, err)
+	assert.Equal(t, int32(12), r.MaxSteps)
+	assert.Equal(t, DefaultBackoff(), r.B)
+}
+
+func TestFromEnvErrors(t *testing.T) {
+	t.Setenv("BAD_MIN_BACKOFF", "10")
+	t.Setenv("BAD_EXP_FACTOR", "Inf")
+	t.Setenv("BAD_MAX_STEPS", "many")
+	base := *NewRetryable(3)
+	r, err := FromEnv("BAD", base)
+	valErr := &ValidationError{}
+	require.True(t, errors.As(err, &valErr))
+	assert.EqualError(t, err, "invalid environment: "+
+		"BAD_MIN_BACKOFF: time: missing unit in duration \"10\"; "+
+		"BAD_EXP_FACTOR: Inf is not a finite number; "+
+		"BAD_MAX_STEPS: strconv.ParseInt: parsing \"many\": invalid syntax")
+	assert.Equal(t, base.B, r.B)
+
+	t.Setenv("INVALID_MIN_BACKOFF", "2m")
+	t.Setenv("INVALID_MAX_STEPS", "0")
+	r, err = FromEnv("INVALID", base)
+	assert.EqualError(t, err, "invalid retryable: MinBackoff (2m0s) > MaxBackoff (1m0s); "+
+		"MaxSteps (0) is not positive")
+	assert.Equal(t, base.MaxSteps, r.MaxSteps)
+}
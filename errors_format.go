@@ -0,0 +1,192 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// errorsTimeFormat is the layout used for timestamps when formatting Error
+// and Errors.
+const errorsTimeFormat = time.RFC3339Nano
+
+// Format implements fmt.Formatter. The %v and %s verbs print the error's
+// timestamp and message; %+v formats the underlying error with %+v as well.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "Error at %s: %+v", e.When.Format(errorsTimeFormat), e.Err)
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		formatOther(s, verb, e, e.Error())
+	}
+}
+
+// Format implements fmt.Formatter.
+//
+// The %v and %s verbs print a compact summary: the number of errors, the
+// times of the first and last ones, and each distinct error message (in
+// order of first occurrence) with the number of times it occurred.
+//
+// The %+v verb prints every error on its own line, with its offset from the
+// first error.
+func (e *Errors) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.formatVerbose(s)
+			return
+		}
+		e.formatCompact(s)
+	case 's':
+		e.formatCompact(s)
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		formatOther(s, verb, e, e.Error())
+	}
+}
+
+// formatHeader writes the leading summary shared by both formats.
+func (e *Errors) formatHeader(w io.Writer) {
+	io.WriteString(w, "errors retrying: ")
+	switch e.count() {
+	case 0:
+		io.WriteString(w, "no errors")
+	case 1:
+		io.WriteString(w, "1 error")
+	default:
+		fmt.Fprintf(w, "%d errors", e.count())
+	}
+	if e.Dropped > 0 {
+		fmt.Fprintf(w, " (%d dropped)", e.Dropped)
+	}
+}
+
+func (e *Errors) formatCompact(w io.Writer) {
+	e.formatHeader(w)
+	if len(e.Errs) == 0 {
+		return
+	}
+	first, last := e.Errs[0].When, e.Errs[len(e.Errs)-1].When
+	if len(e.Errs) == 1 {
+		fmt.Fprintf(w, " at %s: ", first.Format(errorsTimeFormat))
+	} else {
+		fmt.Fprintf(w, " from %s to %s: ",
+			first.Format(errorsTimeFormat), last.Format(errorsTimeFormat))
+	}
+
+	msgs := []string{}
+	counts := map[string]int{}
+	for _, err := range e.Errs {
+		msg := err.Err.Error()
+		if counts[msg] == 0 {
+			msgs = append(msgs, msg)
+		}
+		counts[msg]++
+	}
+	for i, msg := range msgs {
+		if i > 0 {
+			io.WriteString(w, "; ")
+		}
+		io.WriteString(w, msg)
+		if counts[msg] > 1 {
+			fmt.Fprintf(w, " (x%d)", counts[msg])
+		}
+	}
+}
+
+func (e *Errors) formatVerbose(w io.Writer) {
+	e.formatHeader(w)
+	if len(e.Errs) == 0 {
+		return
+	}
+	first := e.Errs[0].When
+	fmt.Fprintf(w, " starting at %s:", first.Format(errorsTimeFormat))
+	for _, err := range e.Errs {
+		fmt.Fprintf(w, "\n\t+%s: %+v", err.When.Sub(first), err.Err)
+	}
+}
+
+// Format implements fmt.Formatter, prefixing the formatted Errors (see
+// Errors.Format) with the context error.
+func (e *CtxErrors) Format(s fmt.State, verb rune) {
+	msg := fmt.Sprintf("retrying aborted by context: %v", e.CtxErr)
+	if e.Cause != nil {
+		msg += fmt.Sprintf(" (cause: %v)", e.Cause)
+	}
+	formatWrapped(s, verb, e, msg, e.Errors)
+}
+
+// Format implements fmt.Formatter, prefixing the formatted Errors (see
+// Errors.Format) with the exhausted time budget.
+func (e *ElapsedErrors) Format(s fmt.State, verb rune) {
+	formatWrapped(s, verb, e, fmt.Sprintf("retry time budget of %s exhausted", e.MaxElapsed), e.Errors)
+}
+
+// formatMessage formats msg, the message of err, for verb.
+func formatMessage(s fmt.State, verb rune, err error, msg string) {
+	switch verb {
+	case 'v', 's':
+		io.WriteString(s, msg)
+	case 'q':
+		fmt.Fprintf(s, "%q", msg)
+	default:
+		formatOther(s, verb, err, msg)
+	}
+}
+
+// formatWrapped formats err for verb as errs (which may be nil) prefixed with
+// msg.
+func formatWrapped(s fmt.State, verb rune, err error, msg string, errs *Errors) {
+	if errs == nil {
+		errs = &Errors{}
+	}
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%s: %+v", msg, errs)
+			return
+		}
+		fmt.Fprintf(s, "%s: %v", msg, errs)
+	case 's':
+		fmt.Fprintf(s, "%s: %v", msg, errs)
+	case 'q':
+		fmt.Fprintf(s, "%q", fmt.Sprintf("%s: %v", msg, errs))
+	default:
+		formatOther(s, verb, err, fmt.Sprintf("%s: %v", msg, errs))
+	}
+}
+
+// formatOther formats msg, the message of err, for verbs other than %v, %s
+// and %q the way fmt formats errors: %x and %X print msg in hexadecimal, and
+// other verbs print e.g. "%!d(*retry.Errors=msg)".
+func formatOther(s fmt.State, verb rune, err error, msg string) {
+	switch verb {
+	case 'x', 'X':
+		fmt.Fprintf(s, "%"+string(verb), msg)
+	default:
+		fmt.Fprintf(s, "%%!%c(%T=%s)", verb, err, msg)
+	}
+}
@@ -0,0 +1,118 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// verboseErr formats differently with %+v, to check that Errors passes
+// the flag through to the underlying errors.
+type verboseErr struct{}
+
+func (verboseErr) Error() string { return "verbose" }
+
+func (v verboseErr) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprint(s, "verbose\n\t\twith details")
+		return
+	}
+	fmt.Fprint(s, v.Error())
+}
+
+func TestErrorsFormat(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, time.March, 4, 12, 30, 0, 0, time.UTC)
+	at := func(d time.Duration, err error) *Error {
+		return &Error{When: start.Add(d), Err: err}
+	}
+	repeated := &Errors{Errs: []*Error{
+		at(0, errors.New("not ready")),
+		at(250*time.Millisecond, errors.New("not ready")),
+		at(time.Second, errors.New("connection refused")),
+		at(3*time.Second, errors.New("not ready")),
+		at(7*time.Second, verboseErr{}),
+	}}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+	}{
+		{name: "error", err: at(time.Second, errors.New("boom"))},
+		{name: "errors_empty", err: &Errors{}},
+		{name: "errors_single", err: &Errors{Errs: []*Error{at(0, errors.New("boom"))}}},
+		{name: "errors_repeated", err: repeated},
+		{name: "errors_dropped", err: &Errors{
+			Errs:    []*Error{at(0, errors.New("first")), at(time.Minute, errors.New("last"))},
+			Dropped: 40,
+		}},
+		{name: "ctx_errors", err: &CtxErrors{Errors: repeated, CtxErr: context.DeadlineExceeded}},
+		{name: "ctx_errors_cause", err: &CtxErrors{
+			Errors: repeated,
+			CtxErr: context.Canceled,
+			Cause:  errors.New("shutting down"),
+		}},
+		{name: "circuit_open", err: &CircuitOpenError{Errors: repeated}},
+		{name: "elapsed_errors", err: &ElapsedErrors{Errors: repeated, MaxElapsed: 10 * time.Second}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := fmt.Sprintf("%%v:\n%v\n\n%%+v:\n%+v\n", tc.err, tc.err)
+			path := filepath.Join("testdata", "format", tc.name+".golden")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+			assert.Equal(t, fmt.Sprintf("%v", tc.err), fmt.Sprintf("%s", tc.err))
+			assert.Equal(t, fmt.Sprintf("%v", tc.err), tc.err.Error())
+		})
+	}
+}
+
+func TestErrorsFormatOtherVerbs(t *testing.T) {
+	t.Parallel()
+	when := time.Date(2025, time.March, 4, 12, 30, 0, 0, time.UTC)
+	errs := &Errors{Errs: []*Error{{When: when, Err: errors.New("boom")}}}
+	for _, err := range []error{
+		errs.Errs[0],
+		errs,
+		&CtxErrors{Errors: errs, CtxErr: context.Canceled},
+		&ElapsedErrors{Errors: errs, MaxElapsed: time.Second},
+		&CircuitOpenError{Errors: errs},
+		&CircuitOpenError{},
+	} {
+		msg := err.Error()
+		assert.Equal(t, fmt.Sprintf("%%!d(%T=%s)", err, msg), fmt.Sprintf("%d", err))
+		assert.Equal(t, fmt.Sprintf("%x", msg), fmt.Sprintf("%x", err))
+		assert.Equal(t, fmt.Sprintf("%X", msg), fmt.Sprintf("%X", err))
+	}
+	assert.Equal(t, "%!d(*retry.Errors=errors retrying: no errors)", fmt.Sprintf("%d", &Errors{}))
+}
@@ -16,11 +16,14 @@
 
 package retry
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 // Unwrap returns the most recent error that occured during retrying.
 func (e *Errors) Unwrap() error {
-	if len(e.Errs) == 0 {
+	if e == nil || len(e.Errs) == 0 {
 		return nil
 	}
 	return e.Errs[len(e.Errs)-1]
@@ -29,6 +32,9 @@ func (e *Errors) Unwrap() error {
 // Is will return true if any of the underlying errors matches the target.  See
 // https://golang.org/pkg/errors/#Is
 func (e *Errors) Is(target error) bool {
+	if e == nil {
+		return false
+	}
 	for _, err := range e.Errs {
 		if errors.Is(err, target) {
 			return true
@@ -41,6 +47,9 @@ func (e *Errors) Is(target error) bool {
 // sets the argument to that error specifically.  It returns false otherwise,
 // leaving the argument unchanged.  See https://golang.org/pkg/errors/#As
 func (e *Errors) As(target interface{}) bool {
+	if e == nil {
+		return false
+	}
 	for _, err := range e.Errs {
 		if errors.As(err, target) {
 			return true
@@ -48,3 +57,79 @@ func (e *Errors) As(target interface{}) bool {
 	}
 	return false
 }
+
+// Is will return true if the context error, its cause, or any of the
+// underlying errors matches the target.  See
+// https://golang.org/pkg/errors/#Is
+func (e *CtxErrors) Is(target error) bool {
+	if e.CtxErr != nil && errors.Is(e.CtxErr, target) {
+		return true
+	}
+	if e.Cause != nil && errors.Is(e.Cause, target) {
+		return true
+	}
+	return e.Errors.Is(target)
+}
+
+// As will return true if the context error, its cause, or any of the
+// underlying errors matches the target and sets the argument to that error
+// specifically.  It returns false otherwise, leaving the argument unchanged.
+// See https://golang.org/pkg/errors/#As
+func (e *CtxErrors) As(target interface{}) bool {
+	if e.CtxErr != nil && errors.As(e.CtxErr, target) {
+		return true
+	}
+	if e.Cause != nil && errors.As(e.Cause, target) {
+		return true
+	}
+	return e.Errors.As(target)
+}
+
+// Unwrap returns ErrCircuitOpen.
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// Is will return true if ErrCircuitOpen or any of the errors from the
+// attempts made before the circuit breaker rejected one matches the target.
+// See https://golang.org/pkg/errors/#Is
+func (e *CircuitOpenError) Is(target error) bool {
+	return errors.Is(ErrCircuitOpen, target) || e.Errors.Is(target)
+}
+
+// As will return true if any of the errors from the attempts made before
+// the circuit breaker rejected one matches the target and sets the argument
+// to that error specifically.  It returns false otherwise, leaving the
+// argument unchanged.  See https://golang.org/pkg/errors/#As
+func (e *CircuitOpenError) As(target interface{}) bool {
+	return e.Errors.As(target)
+}
+
+// Is will return true if any of the problems matches the target.  See
+// https://golang.org/pkg/errors/#Is
+func (e *ValidationError) Is(target error) bool {
+	for _, err := range e.Problems {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As will return true if any of the problems matches the target and sets the
+// argument to that error specifically.  It returns false otherwise, leaving
+// the argument unchanged.  See https://golang.org/pkg/errors/#As
+func (e *ValidationError) As(target interface{}) bool {
+	for _, err := range e.Problems {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// contextCause returns nil: context.Cause is only available with go1.20 and
+// later.
+func contextCause(ctx context.Context) error {
+	return nil
+}
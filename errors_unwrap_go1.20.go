@@ -16,9 +16,11 @@
 
 package retry
 
+import "context"
+
 // Unwrap returns the errors that occured during retrying.
 func (e *Errors) Unwrap() []error {
-	if len(e.Errs) == 0 {
+	if e == nil || len(e.Errs) == 0 {
 		return nil
 	}
 	out := make([]error, len(e.Errs))
@@ -27,3 +29,32 @@ func (e *Errors) Unwrap() []error {
 	}
 	return out
 }
+
+// Unwrap returns the errors that occured during retrying, followed by the
+// context error and its cause (if different).
+func (e *CtxErrors) Unwrap() []error {
+	out := e.Errors.Unwrap()
+	if e.CtxErr != nil {
+		out = append(out, e.CtxErr)
+	}
+	if e.Cause != nil {
+		out = append(out, e.Cause)
+	}
+	return out
+}
+
+// Unwrap returns ErrCircuitOpen, followed by the errors from the attempts
+// made before the circuit breaker rejected one.
+func (e *CircuitOpenError) Unwrap() []error {
+	return append([]error{ErrCircuitOpen}, e.Errors.Unwrap()...)
+}
+
+// Unwrap returns the problems found by Validate.
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// contextCause returns context.Cause(ctx).
+func contextCause(ctx context.Context) error {
+	return context.Cause(ctx)
+}
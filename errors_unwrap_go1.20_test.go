@@ -0,0 +1,56 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+//go:build go1.20
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type shutdownErr struct{}
+
+func (shutdownErr) Error() string { return "shutting down" }
+
+func TestCtxErrorsCause(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	err := Retry(ctx, DefaultBackoff(), 3, func(ctx context.Context) error {
+		cancel(shutdownErr{})
+		return errors.New("foo")
+	})
+	theErr := &CtxErrors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Equal(t, context.Canceled, theErr.CtxErr)
+	assert.Equal(t, shutdownErr{}, theErr.Cause)
+	assert.True(t, errors.Is(err, context.Canceled))
+	var cause shutdownErr
+	assert.True(t, errors.As(err, &cause))
+	assert.Contains(t, err.Error(), "retrying aborted by context: context canceled (cause: shutting down): ")
+
+	// Without an explicit cause, Cause is left unset.
+	ctx, cancel = context.WithCancelCause(context.Background())
+	err = Retry(ctx, DefaultBackoff(), 3, func(ctx context.Context) error {
+		cancel(nil)
+		return errors.New("foo")
+	})
+	require.True(t, errors.As(err, &theErr))
+	assert.Nil(t, theErr.Cause)
+}
@@ -0,0 +1,151 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// Value is implemented by the command-line flag values provided by this
+// package. In addition to flag.Value, it has the Type method of
+// github.com/spf13/pflag.Value, so the flags registered by RegisterFlags are
+// described nicely when added to a pflag.FlagSet with AddGoFlagSet.
+type Value interface {
+	flag.Value
+	Type() string
+}
+
+var (
+	_ Value = (*Backoff)(nil)
+	_ Value = (*retryableValue)(nil)
+	_ Value = (*int32Value)(nil)
+)
+
+// Set implements flag.Value, parsing the format of String (see
+// UnmarshalText).
+func (b *Backoff) Set(s string) error {
+	return b.UnmarshalText([]byte(s))
+}
+
+// Type implements pflag.Value.
+func (b *Backoff) Type() string {
+	return "backoff"
+}
+
+// DefaultFlagPrefix is the prefix used by RegisterFlags if none is given.
+const DefaultFlagPrefix = "retry"
+
+// RegisterFlags registers flags on fs that set the receiver's tunable
+// parameters, using its current values as their defaults:
+//
+//	-<prefix>.min     B.MinBackoff
+//	-<prefix>.max     B.MaxBackoff
+//	-<prefix>.jitter  B.Jitter
+//	-<prefix>.factor  B.ExpFactor
+//	-<prefix>.steps   MaxSteps
+//
+// It also registers -<prefix> itself (see FlagValue), to set all of them at
+// once from a compact string such as
+// "min=10ms,max=30s,factor=2,jitter=0.1,steps=8". The prefix defaults to
+// DefaultFlagPrefix if empty.
+//
+// Since flags are parsed one at a time, the combination of values isn't
+// validated until the receiver is used (or Validate is called).
+func (r *Retryable) RegisterFlags(fs *flag.FlagSet, prefix string) {
+	if prefix == "" {
+		prefix = DefaultFlagPrefix
+	}
+	fs.Var(r.FlagValue(), prefix,
+		"retry `parameters`, as comma-separated key=value pairs with keys min, max, factor, jitter, mode and steps")
+	fs.DurationVar(&r.B.MinBackoff, prefix+".min", r.B.MinBackoff,
+		"minimum interval to wait between attempts")
+	fs.DurationVar(&r.B.MaxBackoff, prefix+".max", r.B.MaxBackoff,
+		"maximum interval to wait between attempts")
+	fs.Float64Var(&r.B.Jitter, prefix+".jitter", r.B.Jitter,
+		"proportion of the interval by which to randomly adjust it")
+	fs.Float64Var(&r.B.ExpFactor, prefix+".factor", r.B.ExpFactor,
+		"factor by which the interval grows after each attempt")
+	fs.Var((*int32Value)(&r.MaxSteps), prefix+".steps",
+		"maximum `number` of attempts")
+}
+
+// FlagValue returns a Value that sets the receiver's B and MaxSteps from a
+// compact string such as "min=10ms,max=30s,factor=2,jitter=0.1,steps=8", in
+// the format of Backoff.String with an additional "steps" parameter.
+// Parameters that are omitted are left unchanged; the result is validated
+// (as by RetryableConfig.Validate) before it's applied.
+func (r *Retryable) FlagValue() Value {
+	return &retryableValue{r: r}
+}
+
+// retryableValue implements Value for Retryable.FlagValue.
+type retryableValue struct {
+	r *Retryable
+}
+
+func (v *retryableValue) String() string {
+	// The flag package calls String on a zero value to check whether a
+	// flag's default is its zero value.
+	if v == nil || v.r == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s,steps=%d", v.r.B, v.r.MaxSteps)
+}
+
+func (v *retryableValue) Set(s string) error {
+	cfg := v.r.Config()
+	if err := parseParams(s, func(key, val string) (bool, error) {
+		if key == "steps" {
+			steps, err := strconv.ParseInt(val, 10, 32)
+			cfg.MaxSteps = int32(steps)
+			return true, err
+		}
+		return cfg.Backoff.setParam(key, val)
+	}); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	v.r.B = cfg.Backoff
+	v.r.MaxSteps = cfg.MaxSteps
+	return nil
+}
+
+func (v *retryableValue) Type() string {
+	return "retryable"
+}
+
+// int32Value implements Value for an int32.
+type int32Value int32
+
+func (i *int32Value) String() string {
+	return strconv.FormatInt(int64(*i), 10)
+}
+
+func (i *int32Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return err
+	}
+	*i = int32Value(v)
+	return nil
+}
+
+func (i *int32Value) Type() string {
+	return "int32"
+}
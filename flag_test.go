@@ -0,0 +1,101 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(5)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	r.RegisterFlags(fs, "")
+
+	require.NoError(t, fs.Parse([]string{
+		"-retry.min=10ms", "-retry.max", "30s", "-retry.jitter=0.2",
+		"-retry.factor=2", "-retry.steps=8",
+	}))
+	assert.Equal(t, Backoff{
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+		Jitter:     0.2,
+		ExpFactor:  2,
+	}, r.B)
+	assert.Equal(t, int32(8), r.MaxSteps)
+	assert.Equal(t, "min=10ms,max=30s,factor=2,jitter=0.2,steps=8", fs.Lookup("retry").Value.String())
+
+	// Defaults come from the Retryable.
+	assert.Equal(t, "1ms", fs.Lookup("retry.min").DefValue)
+	assert.Equal(t, "5", fs.Lookup("retry.steps").DefValue)
+
+	assert.Error(t, fs.Parse([]string{"-retry.steps=1e10"}))
+}
+
+func TestRegisterFlagsCompact(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(5)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	r.RegisterFlags(fs, "db.retry")
+
+	require.NoError(t, fs.Parse([]string{
+		"-db.retry=min=10ms,max=30s,factor=2,jitter=0.1,steps=8",
+		"-db.retry.jitter=0.3",
+	}))
+	assert.Equal(t, Backoff{
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+		Jitter:     0.3,
+		ExpFactor:  2,
+	}, r.B)
+	assert.Equal(t, int32(8), r.MaxSteps)
+
+	// Invalid combinations are rejected, leaving the Retryable alone.
+	err := fs.Parse([]string{"-db.retry=min=1m,steps=0"})
+	assert.EqualError(t, err, "invalid value \"min=1m,steps=0\" for flag -db.retry: "+
+		"invalid retryable: MinBackoff (1m0s) > MaxBackoff (30s); MaxSteps (0) is not positive")
+	assert.Equal(t, 10*time.Millisecond, r.B.MinBackoff)
+	assert.Equal(t, int32(8), r.MaxSteps)
+
+	var usage bytes.Buffer
+	fs.SetOutput(&usage)
+	fs.PrintDefaults()
+	assert.Contains(t, usage.String(), "-db.retry.steps number")
+	assert.Contains(t, usage.String(), "-db.retry parameters")
+}
+
+func TestBackoffStringRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, b := range []Backoff{
+		DefaultBackoff(),
+		{MinBackoff: 10 * time.Millisecond, MaxBackoff: 30 * time.Second, ExpFactor: 2, Jitter: 0.1},
+		{MinBackoff: time.Second, MaxBackoff: time.Second, JitterMode: JitterEqual},
+	} {
+		var got Backoff
+		require.NoError(t, got.Set(b.String()))
+		assert.Equal(t, b, got)
+	}
+	var v Value = &Backoff{}
+	assert.Equal(t, "backoff", v.Type())
+}
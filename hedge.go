@@ -0,0 +1,119 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"time"
+
+	clocks "github.com/vimeo/go-clocks"
+)
+
+// clockAfter returns a channel that is closed once d has elapsed according
+// to clock, unless ctx expires first.
+func clockAfter(ctx context.Context, clock clocks.Clock, d time.Duration) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		if clock.SleepFor(ctx, d) {
+			close(ch)
+		}
+	}()
+	return ch
+}
+
+// Hedge calls the function `f`, and if it hasn't returned after the next
+// interval from `B` (or `Strategy`, if set), calls it again concurrently,
+// up to `MaxSteps` concurrent calls. A call that fails doesn't start another
+// early: the next call still waits for the interval, so a failing backend
+// isn't sent back-to-back calls.
+// Hedge returns nil as soon as any call succeeds, cancelling the context
+// passed to the others. If all `MaxSteps` calls fail, it returns an
+// *Errors aggregating their errors; if the context expires first, it
+// returns a *CtxErrors.
+// ShouldRetry, Permanent errors, AttemptTimeout and Clock are honored as by
+// Retry; the other hooks and limits are not.
+func (r *Retryable) Hedge(ctx context.Context, f func(context.Context) error) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	b := r.backoff()
+	filter := r.ShouldRetry
+	if filter == nil {
+		filter = func(err error) bool {
+			return true
+		}
+	}
+
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so that calls still in flight when we return don't block.
+	results := make(chan error, r.MaxSteps)
+	launched, completed := 0, 0
+	var hedgeTimer <-chan struct{}
+	stopTimer := func() {}
+	defer func() { stopTimer() }()
+
+	launch := func() {
+		launched++
+		go func() {
+			results <- r.attempt(hedgeCtx, f)
+		}()
+		stopTimer()
+		hedgeTimer = nil
+		if launched < int(r.MaxSteps) {
+			timerCtx, timerCancel := context.WithCancel(hedgeCtx)
+			hedgeTimer = clockAfter(timerCtx, r.clock(), b.Next())
+			stopTimer = timerCancel
+		}
+	}
+
+	errors := &Errors{}
+	launch()
+	for {
+		select {
+		case err := <-results:
+			completed++
+			if err == nil {
+				return nil
+			}
+			if IsPermanent(err) {
+				return unwrapPermanent(err)
+			}
+			if !filter(err) {
+				return err
+			}
+			errors.add(&Error{
+				When: r.clock().Now(),
+				Err:  err,
+			}, r.ErrorRetention)
+			if completed == int(r.MaxSteps) {
+				return errors
+			}
+		case <-hedgeTimer:
+			launch()
+		case <-ctx.Done():
+			return newCtxErrors(ctx, errors)
+		}
+	}
+}
+
+// Hedge calls the function `f` up to `attempts` times concurrently, starting
+// each call after waiting for the next interval from `b`, and returns as
+// soon as one succeeds. See Retryable.Hedge for details.
+func Hedge(ctx context.Context, b Backoff, attempts int, f func(context.Context) error) error {
+	r := Retryable{B: b, MaxSteps: int32(attempts), Clock: clocks.DefaultClock()}
+	return r.Hedge(ctx, f)
+}
@@ -0,0 +1,154 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+func TestHedgeSlowFirstAttempt(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(3)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	firstCancelled := make(chan struct{})
+	c := make(chan struct{})
+	var calls int32
+	go func() {
+		err := r.Hedge(context.Background(), func(ctx context.Context) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				// hang until the hedged call wins
+				<-ctx.Done()
+				close(firstCancelled)
+				return ctx.Err()
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	assert.Equal(t, []time.Time{fc.Now().Add(time.Second)}, fc.Sleepers())
+	fc.Advance(time.Second)
+	<-c
+	<-firstCancelled
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
+
+func TestHedgeAllFail(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(3)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	// Each call fails immediately, but the next still waits for the hedge
+	// delay.
+	var calls int32
+	called := make(chan struct{}, 3)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- r.Hedge(context.Background(), func(ctx context.Context) error {
+			called <- struct{}{}
+			return fmt.Errorf("fail %d", atomic.AddInt32(&calls, 1))
+		})
+	}()
+	for i := 0; i < 2; i++ {
+		<-called
+		fc.AwaitSleepers(1)
+		select {
+		case <-called:
+			t.Fatal("call launched before the hedge delay")
+		default:
+		}
+		fc.Advance(time.Second)
+	}
+	err := <-errCh
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	require.Len(t, theErr.Errs, 3)
+	for i, e := range theErr.Errs {
+		assert.EqualError(t, e.Err, fmt.Sprintf("fail %d", i+1))
+	}
+}
+
+func TestHedgeAggregatesErrors(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(2)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	release := make(chan struct{})
+	c := make(chan struct{})
+	var calls int32
+	go func() {
+		err := r.Hedge(context.Background(), func(ctx context.Context) error {
+			n := atomic.AddInt32(&calls, 1)
+			<-release
+			return fmt.Errorf("fail %d", n)
+		})
+		theErr := &Errors{}
+		require.True(t, errors.As(err, &theErr))
+		assert.Len(t, theErr.Errs, 2)
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	// both calls are now in flight
+	for atomic.LoadInt32(&calls) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	<-c
+}
+
+func TestHedgePermanent(t *testing.T) {
+	t.Parallel()
+	base := errors.New("not found")
+	err := Hedge(context.Background(), DefaultBackoff(), 5, func(ctx context.Context) error {
+		return Permanent(base)
+	})
+	assert.Equal(t, base, err)
+}
+
+func TestHedgeCtxCancel(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan struct{})
+	go func() {
+		err := Hedge(ctx, DefaultBackoff(), 1, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		theErr := &CtxErrors{}
+		require.True(t, errors.As(err, &theErr))
+		assert.Equal(t, context.Canceled, theErr.CtxErr)
+		close(c)
+	}()
+	cancel()
+	<-c
+}
@@ -0,0 +1,213 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Outcome classifies how a call to Retryable.Retry finished.
+type Outcome uint8
+
+const (
+	// OutcomeSuccess indicates that an attempt succeeded.
+	OutcomeSuccess Outcome = iota
+	// OutcomeExhausted indicates that MaxSteps attempts failed (Retry
+	// returned an *Errors).
+	OutcomeExhausted
+	// OutcomeCtxAbort indicates that the context expired, or would have
+	// before the next attempt (Retry returned a *CtxErrors).
+	OutcomeCtxAbort
+	// OutcomeElapsed indicates that MaxElapsed would have been exceeded
+	// (Retry returned an *ElapsedErrors).
+	OutcomeElapsed
+	// OutcomeAborted indicates that Retry returned early with any other
+	// error, e.g. one rejected by ShouldRetry or marked Permanent.
+	OutcomeAborted
+	// OutcomeCircuitOpen indicates that Retryable.Breaker rejected an
+	// attempt (Retry returned a *CircuitOpenError).
+	OutcomeCircuitOpen
+
+	numOutcomes = iota
+)
+
+// String returns a short snake_case name for the outcome, suitable for use
+// as a metric label.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeExhausted:
+		return "exhausted"
+	case OutcomeCtxAbort:
+		return "context"
+	case OutcomeElapsed:
+		return "elapsed"
+	case OutcomeAborted:
+		return "aborted"
+	case OutcomeCircuitOpen:
+		return "circuit_open"
+	default:
+		return "unknown"
+	}
+}
+
+// outcomeOf classifies an error returned by Retryable.Retry.
+func outcomeOf(err error) Outcome {
+	switch err.(type) {
+	case nil:
+		return OutcomeSuccess
+	case *Errors:
+		return OutcomeExhausted
+	case *CtxErrors:
+		return OutcomeCtxAbort
+	case *ElapsedErrors:
+		return OutcomeElapsed
+	case *CircuitOpenError:
+		return OutcomeCircuitOpen
+	default:
+		return OutcomeAborted
+	}
+}
+
+// Metrics receives measurements from Retryable.Retry.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Attempt is called before every attempt.
+	Attempt()
+	// Retry is called after a failed attempt that will be retried, before
+	// sleeping for delay.
+	Retry(delay time.Duration)
+	// Done is called once for every call to Retry, with its outcome and
+	// the time elapsed since it started.
+	Done(outcome Outcome, latency time.Duration)
+}
+
+// DefaultMetricsBuckets are the upper bounds, in seconds, of the histogram
+// buckets used by the Metrics implementations in this package.
+var DefaultMetricsBuckets = []float64{
+	.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300,
+}
+
+// histogram accumulates observations into cumulative buckets.
+type histogram struct {
+	bounds []float64
+	// counts[i] is the number of observations <= bounds[i] (and >
+	// bounds[i-1]); the final entry counts everything beyond the last
+	// bound.
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) histogram {
+	b := append([]float64(nil), bounds...)
+	sort.Float64s(b)
+	return histogram{
+		bounds: b,
+		counts: make([]uint64, len(b)+1),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	h.counts[sort.SearchFloat64s(h.bounds, v)]++
+	h.sum += v
+	h.count++
+}
+
+// cumulative returns the number of observations <= each bound, followed by
+// the total count.
+func (h *histogram) cumulative() []uint64 {
+	out := make([]uint64, len(h.counts))
+	total := uint64(0)
+	for i, c := range h.counts {
+		total += c
+		out[i] = total
+	}
+	return out
+}
+
+// clone returns a deep copy of h.
+func (h *histogram) clone() histogram {
+	c := *h
+	c.counts = append([]uint64(nil), h.counts...)
+	return c
+}
+
+// metricsSet is the Metrics implementation underlying the exporters in this
+// package.
+type metricsSet struct {
+	mu       sync.Mutex
+	attempts uint64
+	retries  uint64
+	outcomes [numOutcomes]uint64
+	delays   histogram
+	latency  histogram
+}
+
+func newMetricsSet() *metricsSet {
+	return &metricsSet{
+		delays:  newHistogram(DefaultMetricsBuckets),
+		latency: newHistogram(DefaultMetricsBuckets),
+	}
+}
+
+// Attempt implements Metrics
+func (m *metricsSet) Attempt() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts++
+}
+
+// Retry implements Metrics
+func (m *metricsSet) Retry(delay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+	m.delays.observe(delay.Seconds())
+}
+
+// Done implements Metrics
+func (m *metricsSet) Done(outcome Outcome, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if outcome < numOutcomes {
+		m.outcomes[outcome]++
+	}
+	m.latency.observe(latency.Seconds())
+}
+
+// snapshot returns a consistent copy of m.
+func (m *metricsSet) snapshot() metricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return metricsSnapshot{
+		attempts: m.attempts,
+		retries:  m.retries,
+		outcomes: m.outcomes,
+		delays:   m.delays.clone(),
+		latency:  m.latency.clone(),
+	}
+}
+
+type metricsSnapshot struct {
+	attempts uint64
+	retries  uint64
+	outcomes [numOutcomes]uint64
+	delays   histogram
+	latency  histogram
+}
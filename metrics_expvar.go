@@ -0,0 +1,88 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// ExpvarName is the name of the expvar.Map under which NewExpvarMetrics
+// publishes its per-operation metrics.
+const ExpvarName = "go-retry"
+
+var (
+	expvarMu   sync.Mutex
+	expvarRoot *expvar.Map
+	expvarOps  = map[string]*metricsSet{}
+)
+
+// NewExpvarMetrics returns a Metrics implementation publishing to expvar, as
+// the `operation` key of the map published as ExpvarName. Calls with the same
+// operation share the same counters.
+//
+// Each operation's value is an object with counts of attempts, retries and
+// calls to Retry by Outcome (keyed by Outcome.String()), as well as
+// histograms (in seconds) of backoff delays and latency of calls to Retry.
+func NewExpvarMetrics(operation string) Metrics {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvarRoot == nil {
+		expvarRoot = expvar.NewMap(ExpvarName)
+	}
+	if m, ok := expvarOps[operation]; ok {
+		return m
+	}
+	m := newMetricsSet()
+	expvarOps[operation] = m
+	expvarRoot.Set(operation, expvar.Func(func() interface{} {
+		s := m.snapshot()
+		return s.expvarValue()
+	}))
+	return m
+}
+
+// expvarValue returns a JSON-marshalable representation of s.
+func (s *metricsSnapshot) expvarValue() map[string]interface{} {
+	out := map[string]interface{}{
+		"attempts":              s.attempts,
+		"retries":               s.retries,
+		"backoff_delay_seconds": s.delays.expvarValue(),
+		"latency_seconds":       s.latency.expvarValue(),
+	}
+	for o, c := range s.outcomes {
+		out[Outcome(o).String()] = c
+	}
+	return out
+}
+
+// expvarValue returns a JSON-marshalable representation of h, with
+// cumulative bucket counts keyed by upper bound.
+func (h *histogram) expvarValue() map[string]interface{} {
+	buckets := make(map[string]uint64, len(h.counts))
+	for i, c := range h.cumulative() {
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = strconv.FormatFloat(h.bounds[i], 'g', -1, 64)
+		}
+		buckets[le] = c
+	}
+	return map[string]interface{}{
+		"buckets": buckets,
+		"sum":     h.sum,
+		"count":   h.count,
+	}
+}
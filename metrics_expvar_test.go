@@ -0,0 +1,69 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expvarTestRuns distinguishes the operation names used by repeated runs of
+// TestExpvarMetrics (e.g. with -count), since expvars are global.
+var expvarTestRuns int
+
+func TestExpvarMetrics(t *testing.T) {
+	expvarTestRuns++
+	op := fmt.Sprintf("expvar-test-%d", expvarTestRuns)
+	m := NewExpvarMetrics(op)
+	assert.Same(t, m, NewExpvarMetrics(op))
+
+	m.Attempt()
+	m.Attempt()
+	m.Retry(30 * time.Millisecond)
+	m.Done(OutcomeSuccess, 40*time.Millisecond)
+
+	root, ok := expvar.Get(ExpvarName).(*expvar.Map)
+	require.True(t, ok)
+	v := root.Get(op)
+	require.NotNil(t, v)
+
+	out := struct {
+		Attempts  uint64 `json:"attempts"`
+		Retries   uint64 `json:"retries"`
+		Success   uint64 `json:"success"`
+		Exhausted uint64 `json:"exhausted"`
+		Delays    struct {
+			Buckets map[string]uint64 `json:"buckets"`
+			Sum     float64           `json:"sum"`
+			Count   uint64            `json:"count"`
+		} `json:"backoff_delay_seconds"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &out))
+	assert.EqualValues(t, 2, out.Attempts)
+	assert.EqualValues(t, 1, out.Retries)
+	assert.EqualValues(t, 1, out.Success)
+	assert.EqualValues(t, 0, out.Exhausted)
+	assert.EqualValues(t, 1, out.Delays.Count)
+	assert.InDelta(t, 0.03, out.Delays.Sum, 1e-9)
+	assert.EqualValues(t, 0, out.Delays.Buckets["0.025"])
+	assert.EqualValues(t, 1, out.Delays.Buckets["0.05"])
+	assert.EqualValues(t, 1, out.Delays.Buckets["+Inf"])
+}
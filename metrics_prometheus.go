@@ -0,0 +1,158 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PrometheusMetrics collects Metrics for any number of operations and exports
+// them in the Prometheus text exposition format, labeled by operation,
+// without depending on the Prometheus client libraries.
+//
+// The exported metric families are:
+//   - retry_attempts_total: counter of attempts
+//   - retry_retries_total: counter of retries (sleeps between attempts)
+//   - retry_calls_total: counter of calls to Retry, by outcome (see Outcome)
+//   - retry_backoff_delay_seconds: histogram of backoff delays
+//   - retry_latency_seconds: histogram of the total latency of calls to Retry
+type PrometheusMetrics struct {
+	mu  sync.Mutex
+	ops map[string]*metricsSet
+}
+
+// NewPrometheusMetrics returns an empty PrometheusMetrics.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{ops: map[string]*metricsSet{}}
+}
+
+// Operation returns the Metrics for the named operation, creating it if
+// necessary.
+func (p *PrometheusMetrics) Operation(name string) Metrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if m, ok := p.ops[name]; ok {
+		return m
+	}
+	m := newMetricsSet()
+	p.ops[name] = m
+	return m
+}
+
+// ServeHTTP implements http.Handler, writing the current values of all
+// metrics in the text exposition format.
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the current values of all metrics to w in the text
+// exposition format.
+func (p *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	names := make([]string, 0, len(p.ops))
+	for name := range p.ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	snaps := make([]metricsSnapshot, len(names))
+	for i, name := range names {
+		snaps[i] = p.ops[name].snapshot()
+	}
+	p.mu.Unlock()
+
+	cw := countingWriter{w: bufio.NewWriter(w)}
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = `operation="` + escapeLabelValue(name) + `"`
+	}
+
+	fmt.Fprintf(&cw, "# HELP retry_attempts_total Number of attempts made.\n")
+	fmt.Fprintf(&cw, "# TYPE retry_attempts_total counter\n")
+	for i := range snaps {
+		fmt.Fprintf(&cw, "retry_attempts_total{%s} %d\n", labels[i], snaps[i].attempts)
+	}
+	fmt.Fprintf(&cw, "# HELP retry_retries_total Number of retries after failed attempts.\n")
+	fmt.Fprintf(&cw, "# TYPE retry_retries_total counter\n")
+	for i := range snaps {
+		fmt.Fprintf(&cw, "retry_retries_total{%s} %d\n", labels[i], snaps[i].retries)
+	}
+	fmt.Fprintf(&cw, "# HELP retry_calls_total Number of completed retry loops by outcome.\n")
+	fmt.Fprintf(&cw, "# TYPE retry_calls_total counter\n")
+	for i := range snaps {
+		for o, c := range snaps[i].outcomes {
+			fmt.Fprintf(&cw, "retry_calls_total{%s,outcome=%q} %d\n", labels[i], Outcome(o).String(), c)
+		}
+	}
+	writePromHistogram(&cw, "retry_backoff_delay_seconds", "Backoff delays between attempts.",
+		labels, snaps, func(s *metricsSnapshot) *histogram { return &s.delays })
+	writePromHistogram(&cw, "retry_latency_seconds", "Total latency of retry loops.",
+		labels, snaps, func(s *metricsSnapshot) *histogram { return &s.latency })
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	err := cw.w.Flush()
+	return cw.n, err
+}
+
+func writePromHistogram(w io.Writer, name, help string, labels []string,
+	snaps []metricsSnapshot, hist func(*metricsSnapshot) *histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for i := range snaps {
+		h := hist(&snaps[i])
+		for j, c := range h.cumulative() {
+			le := "+Inf"
+			if j < len(h.bounds) {
+				le = strconv.FormatFloat(h.bounds[j], 'g', -1, 64)
+			}
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, labels[i], le, c)
+		}
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels[i], strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels[i], h.count)
+	}
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes a label value for the text exposition format.
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+// countingWriter counts bytes written and latches the first error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,95 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	p := NewPrometheusMetrics()
+	// Only use a single bucket to keep the expected output manageable.
+	for _, name := range []string{"write", "re\"ad"} {
+		m := p.Operation(name).(*metricsSet)
+		m.delays = newHistogram([]float64{1})
+		m.latency = newHistogram([]float64{1})
+	}
+	assert.Same(t, p.Operation("write"), p.Operation("write"))
+
+	w := p.Operation("write")
+	w.Attempt()
+	w.Attempt()
+	w.Retry(500 * time.Millisecond)
+	w.Done(OutcomeSuccess, 2*time.Second)
+
+	r := p.Operation("re\"ad")
+	r.Attempt()
+	r.Done(OutcomeCtxAbort, 250*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	expected := strings.Join([]string{
+		`# HELP retry_attempts_total Number of attempts made.`,
+		`# TYPE retry_attempts_total counter`,
+		`retry_attempts_total{operation="re\"ad"} 1`,
+		`retry_attempts_total{operation="write"} 2`,
+		`# HELP retry_retries_total Number of retries after failed attempts.`,
+		`# TYPE retry_retries_total counter`,
+		`retry_retries_total{operation="re\"ad"} 0`,
+		`retry_retries_total{operation="write"} 1`,
+		`# HELP retry_calls_total Number of completed retry loops by outcome.`,
+		`# TYPE retry_calls_total counter`,
+		`retry_calls_total{operation="re\"ad",outcome="success"} 0`,
+		`retry_calls_total{operation="re\"ad",outcome="exhausted"} 0`,
+		`retry_calls_total{operation="re\"ad",outcome="context"} 1`,
+		`retry_calls_total{operation="re\"ad",outcome="elapsed"} 0`,
+		`retry_calls_total{operation="re\"ad",outcome="aborted"} 0`,
+		`retry_calls_total{operation="re\"ad",outcome="circuit_open"} 0`,
+		`retry_calls_total{operation="write",outcome="success"} 1`,
+		`retry_calls_total{operation="write",outcome="exhausted"} 0`,
+		`retry_calls_total{operation="write",outcome="context"} 0`,
+		`retry_calls_total{operation="write",outcome="elapsed"} 0`,
+		`retry_calls_total{operation="write",outcome="aborted"} 0`,
+		`retry_calls_total{operation="write",outcome="circuit_open"} 0`,
+		`# HELP retry_backoff_delay_seconds Backoff delays between attempts.`,
+		`# TYPE retry_backoff_delay_seconds histogram`,
+		`retry_backoff_delay_seconds_bucket{operation="re\"ad",le="1"} 0`,
+		`retry_backoff_delay_seconds_bucket{operation="re\"ad",le="+Inf"} 0`,
+		`retry_backoff_delay_seconds_sum{operation="re\"ad"} 0`,
+		`retry_backoff_delay_seconds_count{operation="re\"ad"} 0`,
+		`retry_backoff_delay_seconds_bucket{operation="write",le="1"} 1`,
+		`retry_backoff_delay_seconds_bucket{operation="write",le="+Inf"} 1`,
+		`retry_backoff_delay_seconds_sum{operation="write"} 0.5`,
+		`retry_backoff_delay_seconds_count{operation="write"} 1`,
+		`# HELP retry_latency_seconds Total latency of retry loops.`,
+		`# TYPE retry_latency_seconds histogram`,
+		`retry_latency_seconds_bucket{operation="re\"ad",le="1"} 1`,
+		`retry_latency_seconds_bucket{operation="re\"ad",le="+Inf"} 1`,
+		`retry_latency_seconds_sum{operation="re\"ad"} 0.25`,
+		`retry_latency_seconds_count{operation="re\"ad"} 1`,
+		`retry_latency_seconds_bucket{operation="write",le="1"} 0`,
+		`retry_latency_seconds_bucket{operation="write",le="+Inf"} 1`,
+		`retry_latency_seconds_sum{operation="write"} 2`,
+		`retry_latency_seconds_count{operation="write"} 1`,
+	}, "\n") + "\n"
+	assert.Equal(t, expected, rec.Body.String())
+}
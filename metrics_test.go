@@ -0,0 +1,105 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+func TestHistogramObserve(t *testing.T) {
+	h := newHistogram([]float64{1, 0.5, 2})
+	for _, v := range []float64{0.1, 0.5, 0.7, 2, 3, 10} {
+		h.observe(v)
+	}
+	assert.Equal(t, []float64{0.5, 1, 2}, h.bounds)
+	assert.Equal(t, []uint64{2, 1, 1, 2}, h.counts)
+	assert.Equal(t, []uint64{2, 3, 4, 6}, h.cumulative())
+	assert.EqualValues(t, 6, h.count)
+	assert.InDelta(t, 16.3, h.sum, 1e-9)
+}
+
+func TestOutcomeOf(t *testing.T) {
+	assert.Equal(t, OutcomeSuccess, outcomeOf(nil))
+	assert.Equal(t, OutcomeExhausted, outcomeOf(&Errors{}))
+	assert.Equal(t, OutcomeCtxAbort, outcomeOf(&CtxErrors{}))
+	assert.Equal(t, OutcomeElapsed, outcomeOf(&ElapsedErrors{}))
+	assert.Equal(t, OutcomeCircuitOpen, outcomeOf(&CircuitOpenError{}))
+	assert.Equal(t, OutcomeAborted, outcomeOf(errors.New("foo")))
+}
+
+func TestRetryableMetrics(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := make(chan struct{})
+	fc := fake.NewClock(time.Now())
+	m := newMetricsSet()
+
+	r := NewRetryable(18)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+	r.Metrics = m
+
+	go func() {
+		q := 0
+		err := r.Retry(ctx, func(ctx context.Context) error {
+			q++
+			if q == 3 {
+				return nil
+			}
+			return fmt.Errorf("foo")
+		})
+		assert.NoError(t, err)
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	<-c
+
+	r.MaxSteps = 1
+	c = make(chan struct{})
+	go func() {
+		r.Retry(ctx, func(ctx context.Context) error {
+			return fmt.Errorf("foo")
+		})
+		close(c)
+	}()
+	// Retry backs off after the final attempt as well, but that's not a
+	// retry.
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	<-c
+
+	s := m.snapshot()
+	assert.EqualValues(t, 4, s.attempts)
+	assert.EqualValues(t, 2, s.retries)
+	assert.EqualValues(t, 1, s.outcomes[OutcomeSuccess])
+	assert.EqualValues(t, 1, s.outcomes[OutcomeExhausted])
+	// Every attempt but the first of each call is a retry.
+	calls := s.outcomes[OutcomeSuccess] + s.outcomes[OutcomeExhausted]
+	assert.EqualValues(t, s.attempts-calls, s.retries)
+	assert.EqualValues(t, 2, s.delays.count)
+	assert.EqualValues(t, 2, s.delays.sum)
+	assert.EqualValues(t, 2, s.latency.count)
+	assert.EqualValues(t, 3, s.latency.sum)
+}
@@ -0,0 +1,58 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import "errors"
+
+// permanentError marks an error as not worth retrying.
+type permanentError struct {
+	err error
+}
+
+// Permanent wraps err so Retryable.Retry returns it immediately rather than
+// retrying, regardless of ShouldRetry. The marker may be anywhere in the
+// error-chain returned by the retried function. Permanent returns nil if err
+// is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent returns true if err, or any error it wraps, was marked with
+// Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Error implements the error interface.
+func (p *permanentError) Error() string {
+	return p.err.Error()
+}
+
+// Unwrap follows go-1.13-style wrapping semantics.
+func (p *permanentError) Unwrap() error {
+	return p.err
+}
+
+// unwrapPermanent strips a Permanent marker from the top of err's chain.
+func unwrapPermanent(err error) error {
+	if p, ok := err.(*permanentError); ok {
+		return p.err
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("not found")
+	assert.Nil(t, Permanent(nil))
+	assert.False(t, IsPermanent(base))
+	assert.False(t, IsPermanent(nil))
+
+	p := Permanent(base)
+	assert.True(t, IsPermanent(p))
+	assert.True(t, errors.Is(p, base))
+	assert.Equal(t, base.Error(), p.Error())
+
+	wrapped := fmt.Errorf("fetching thing: %w", p)
+	assert.True(t, IsPermanent(wrapped))
+	assert.True(t, errors.Is(wrapped, base))
+}
+
+func TestRetryPermanent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	backoff := DefaultBackoff()
+	backoff.MinBackoff = time.Microsecond
+	base := errors.New("not found")
+
+	q := 0
+	err := Retry(ctx, backoff, 18, func(ctx context.Context) error {
+		q++
+		if q == 2 {
+			return Permanent(base)
+		}
+		return fmt.Errorf("foo")
+	})
+	assert.Equal(t, 2, q)
+	// The marker is stripped on return
+	assert.Equal(t, base, err)
+}
+
+func TestRetryPermanentWrapped(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	base := errors.New("not found")
+
+	r := NewRetryable(18)
+	r.ShouldRetry = func(error) bool {
+		t.Error("ShouldRetry should not be called for permanent errors")
+		return true
+	}
+	q := 0
+	err := r.Retry(ctx, func(ctx context.Context) error {
+		q++
+		return fmt.Errorf("lookup: %w", Permanent(base))
+	})
+	assert.Equal(t, 1, q)
+	assert.True(t, errors.Is(err, base))
+	assert.EqualError(t, err, "lookup: not found")
+}
@@ -0,0 +1,103 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import "context"
+
+type pollProgressKey struct{}
+
+// pollProgress is stashed in the context passed to the function given to
+// Poll, so ReportProgress can reach it.
+type pollProgress struct {
+	reported bool
+}
+
+// ReportProgress tells Poll that the current poll made progress (e.g. a job
+// advanced a stage), so it resets its backoff to poll again promptly. It is a
+// no-op if ctx was not passed to a poll function by Poll.
+func ReportProgress(ctx context.Context) {
+	if p, ok := ctx.Value(pollProgressKey{}).(*pollProgress); ok {
+		p.reported = true
+	}
+}
+
+// Poll calls the function `f` until it reports that it is done, waiting
+// between calls according to `r.B` (or `r.Strategy`).
+//
+// Unlike with Retry, a call that isn't done isn't a failure: it isn't
+// recorded or counted against `r.MaxSteps`. Errors returned by `f` are
+// either fatal (marked Permanent, or rejected by `r.ShouldRetry`), in which
+// case Poll returns them immediately, or transient, in which case they are
+// recorded and polling continues, up to `r.MaxSteps` transient errors.
+// If `f` calls ReportProgress with the context it was passed, the backoff is
+// reset before the next poll.
+//
+// Poll returns nil once `f` returns (true, nil), a fatal error, an *Errors
+// once `r.MaxSteps` transient errors have occurred, a *CtxErrors if the
+// context expires (or would before the next poll), or an *ElapsedErrors if
+// `r.MaxElapsed` would be exceeded. `r.AttemptTimeout` bounds each call to
+// `f`; r's hooks, Metrics, Tracer, Breaker and Budget are not used.
+func Poll(ctx context.Context, r *Retryable, f func(ctx context.Context) (done bool, err error)) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	b := r.backoff()
+	filter := r.ShouldRetry
+	if filter == nil {
+		filter = func(err error) bool {
+			return true
+		}
+	}
+
+	start := r.clock().Now()
+	errors := &Errors{}
+	for {
+		progress := &pollProgress{}
+		done := false
+		err := r.attempt(context.WithValue(ctx, pollProgressKey{}, progress), func(ctx context.Context) error {
+			var pollErr error
+			done, pollErr = f(ctx)
+			return pollErr
+		})
+		if err == nil && done {
+			return nil
+		}
+		if err != nil {
+			if IsPermanent(err) {
+				return unwrapPermanent(err)
+			}
+			if !filter(err) {
+				return err
+			}
+			errors.add(&Error{
+				When: r.clock().Now(),
+				Err:  err,
+			}, r.ErrorRetention)
+			if int64(errors.count()) >= int64(r.MaxSteps) {
+				return errors
+			}
+		}
+		if progress.reported {
+			b.Reset()
+		}
+		nextStep, stopErr := r.nextStep(ctx, b, start, err, errors)
+		if stopErr != nil {
+			return stopErr
+		}
+		if !r.clock().SleepFor(ctx, nextStep) {
+			return newCtxErrors(ctx, errors)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+func TestPollNotDoneIsNotRecorded(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(2)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+
+	calls := 0
+	err := Poll(context.Background(), r, func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 10, nil
+	})
+	require.NoError(t, err)
+	// Not-done polls don't count against MaxSteps.
+	assert.Equal(t, 10, calls)
+}
+
+func TestPollTransientErrors(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(3)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+
+	calls := 0
+	err := Poll(context.Background(), r, func(ctx context.Context) (bool, error) {
+		calls++
+		if calls%2 == 0 {
+			return false, errors.New("flaky")
+		}
+		return false, nil
+	})
+	assert.Equal(t, 6, calls)
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	require.Len(t, theErr.Errs, 3)
+	assert.EqualError(t, theErr.Errs[2].Err, "flaky")
+}
+
+func TestPollFatalErrors(t *testing.T) {
+	t.Parallel()
+	fatal := errors.New("fatal")
+	r := NewRetryable(10)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+	r.ShouldRetry = func(err error) bool {
+		return !errors.Is(err, fatal)
+	}
+
+	calls := 0
+	err := Poll(context.Background(), r, func(ctx context.Context) (bool, error) {
+		calls++
+		if calls == 3 {
+			return false, fatal
+		}
+		return false, errors.New("transient")
+	})
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, fatal, err)
+
+	calls = 0
+	perm := errors.New("permanent")
+	err = Poll(context.Background(), NewRetryable(10), func(ctx context.Context) (bool, error) {
+		calls++
+		// An error takes precedence over done.
+		return true, Permanent(perm)
+	})
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, perm, err)
+}
+
+func TestPollProgressResetsBackoff(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(1)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Minute, ExpFactor: 2}
+
+	calls := 0
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Poll(context.Background(), r, func(ctx context.Context) (bool, error) {
+			calls++
+			if calls == 3 {
+				ReportProgress(ctx)
+			}
+			return calls == 5, nil
+		})
+	}()
+	// The backoff grows until progress is reported after the third poll.
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, time.Second, 2 * time.Second} {
+		fc.AwaitSleepers(1)
+		assert.Equal(t, fc.Now().Add(d), fc.Sleepers()[0])
+		fc.Advance(d)
+	}
+	require.NoError(t, <-errCh)
+	assert.Equal(t, 5, calls)
+}
+
+func TestPollDeadline(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(1)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+	ctx, cancel := context.WithDeadline(context.Background(), fc.Now().Add(1500*time.Millisecond))
+	defer cancel()
+
+	calls := 0
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Poll(ctx, r, func(ctx context.Context) (bool, error) {
+			calls++
+			return false, nil
+		})
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	err := <-errCh
+	assert.Equal(t, 2, calls)
+	theErr := &CtxErrors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Equal(t, context.DeadlineExceeded, theErr.CtxErr)
+	assert.Empty(t, theErr.Errs)
+}
+
+func TestPollInvalidBackoff(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(1)
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Millisecond}
+	err := Poll(context.Background(), r, func(ctx context.Context) (bool, error) {
+		t.Fatal("unexpected poll")
+		return true, nil
+	})
+	assert.EqualError(t, err, "invalid retryable: MinBackoff (1s) > MaxBackoff (1ms)")
+}
+
+func TestPollErrorRetention(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(5)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+	r.ErrorRetention = ErrorRetention{Last: 1}
+
+	calls := 0
+	err := Poll(context.Background(), r, func(ctx context.Context) (bool, error) {
+		calls++
+		return false, errors.New("flaky")
+	})
+	// Dropped errors still count against MaxSteps.
+	assert.Equal(t, 5, calls)
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Len(t, theErr.Errs, 1)
+	assert.Equal(t, 4, theErr.Dropped)
+}
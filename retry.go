@@ -27,17 +27,78 @@ type Retryable struct {
 	// Backoff parameters to use for retry
 	B Backoff
 
+	// Strategy, if non-nil, overrides B as the source of intervals to wait
+	// between attempts. It is cloned (and reset) on every call to Retry,
+	// so a single Retryable may be shared across goroutines as long as
+	// CloneStrategy is thread-safe.
+	Strategy BackoffStrategy
+
 	// ShouldRetry is a filter function to indicate whether to continue
 	// iterating based on the error.
 	// An implementation that uniformly returns true is used if nil
+	// Errors marked with Permanent are never retried, and are not passed
+	// to ShouldRetry.
 	ShouldRetry func(error) bool
 
 	// Maximum retry attempts
 	MaxSteps int32
 
+	// MaxElapsed, if positive, bounds the total time (as measured by Clock)
+	// since the first attempt. Retry returns an *ElapsedErrors rather than
+	// sleeping past this budget.
+	MaxElapsed time.Duration
+
+	// AttemptTimeout, if positive, bounds each individual call to the
+	// function passed to Retry, using a child context that expires (with
+	// context.DeadlineExceeded) after AttemptTimeout as measured by Clock.
+	// An attempt that times out while the outer context is still live is
+	// retried like any other error (subject to ShouldRetry).
+	AttemptTimeout time.Duration
+
 	// Clock provides a clock to use when backing off (if nil, uses
 	// github.com/vimeo/go-clocks.DefaultClock())
 	Clock clocks.Clock
+
+	// OnRetry, if non-nil, is called after a failed attempt that will be
+	// retried, before sleeping, with the (1-indexed) number of the attempt
+	// that failed, its error and the interval about to be waited.
+	OnRetry func(attempt int, err error, delay time.Duration)
+
+	// OnSuccess, if non-nil, is called when an attempt succeeds, with the
+	// number of attempts made and the time elapsed since the first one.
+	OnSuccess func(attempts int, elapsed time.Duration)
+
+	// OnGiveUp, if non-nil, is called with the number of attempts made and
+	// the error Retry is about to return, when it gives up. That error is
+	// usually an *Errors, *CtxErrors or *ElapsedErrors, but may be a
+	// non-retryable error returned by an attempt.
+	OnGiveUp func(attempts int, err error)
+
+	// Metrics, if non-nil, receives counts of attempts, retries and
+	// outcomes, as well as backoff delays and latencies.
+	Metrics Metrics
+
+	// Tracer, if non-nil, is used to start a span for each call to Retry,
+	// with a child span for each attempt.
+	Tracer Tracer
+
+	// Breaker, if non-nil, is consulted before each attempt, and told the
+	// result of each attempt. If it rejects an attempt, or would still
+	// reject the next one after backing off, Retry returns a
+	// *CircuitOpenError immediately.
+	Breaker *CircuitBreaker
+
+	// ErrorRetention bounds the number of errors kept in the returned
+	// Errors; the rest are only counted in Errors.Dropped. By default, all
+	// errors are kept.
+	ErrorRetention ErrorRetention
+
+	// Budget, if non-nil, limits retries to a fraction of first attempts
+	// across all Retryables sharing it. When it is exhausted, Retry returns
+	// the last attempt's error immediately rather than sleeping. It is only
+	// drawn on when another attempt would follow, so a Retry that runs out
+	// of MaxSteps doesn't spend it.
+	Budget *RetryBudget
 }
 
 // NewRetryable returns a newly constructed Retryable instance
@@ -50,6 +111,36 @@ func NewRetryable(MaxSteps int32) *Retryable {
 	}
 }
 
+// backoff returns a freshly reset copy of the BackoffStrategy to use for a
+// single call to Retry.
+func (r *Retryable) backoff() BackoffStrategy {
+	if r.Strategy != nil {
+		s := r.Strategy.CloneStrategy()
+		s.Reset()
+		return s
+	}
+	b := r.B.Clone()
+	b.Reset()
+	return &b
+}
+
+// attempt makes a single call to f, bounded by AttemptTimeout if set.
+func (r *Retryable) attempt(ctx context.Context, f func(context.Context) error) error {
+	if r.AttemptTimeout <= 0 {
+		return f(ctx)
+	}
+	attemptCtx, cancel := withClockTimeout(ctx, r.clock(), r.AttemptTimeout)
+	defer cancel()
+	return f(attemptCtx)
+}
+
+func (r *Retryable) tracer() Tracer {
+	if r.Tracer == nil {
+		return noopTracer{}
+	}
+	return r.Tracer
+}
+
 func (r *Retryable) clock() clocks.Clock {
 	if r.Clock == nil {
 		return clocks.DefaultClock()
@@ -58,58 +149,153 @@ func (r *Retryable) clock() clocks.Clock {
 }
 
 // Retry calls the function `f` at most `MaxSteps` times using the exponential
-// backoff parameters defined in `B`, or until the context expires.
+// backoff parameters defined in `B` (or the BackoffStrategy in `Strategy`, if
+// set), or until the context expires.
+// Retry backs off after the final attempt too, so it returns a *CtxErrors or
+// *ElapsedErrors rather than *Errors if that wait would run past the context's
+// deadline or MaxElapsed. That wait is not a retry, though, so neither OnRetry
+// nor Metrics.Retry is called for it, and neither Breaker nor Budget is asked
+// about it. Attempts behaves the same way.
 func (r *Retryable) Retry(ctx context.Context, f func(context.Context) error) error {
-	b := r.B.Clone()
-	b.Reset()
-	filter := r.ShouldRetry
-	if filter == nil {
-		filter = func(err error) bool {
-			return true
+	ctx, span := r.tracer().Start(ctx, "retry")
+	defer span.End()
+	span.SetAttributes(Attribute{Key: AttrMaxSteps, Value: int(r.MaxSteps)})
+
+	start := r.clock().Now()
+	attempts, err := r.retry(ctx, start, f)
+	if r.Metrics != nil {
+		r.Metrics.Done(outcomeOf(err), r.clock().Now().Sub(start))
+	}
+	span.SetAttributes(
+		Attribute{Key: AttrAttempts, Value: attempts},
+		Attribute{Key: AttrOutcome, Value: outcomeOf(err).String()})
+	if err != nil {
+		span.RecordError(err)
+	}
+	if err == nil {
+		if r.OnSuccess != nil {
+			r.OnSuccess(attempts, r.clock().Now().Sub(start))
 		}
+		return nil
 	}
-
-	beyondDeadline := func(time.Duration) bool {
-		return false
+	if r.OnGiveUp != nil {
+		r.OnGiveUp(attempts, err)
 	}
+	return err
+}
 
-	if dl, ok := ctx.Deadline(); ok {
-		beyondDeadline = func(nextStep time.Duration) bool {
-			remaining := r.clock().Until(dl)
-			return remaining < nextStep
+// retry implements Retry, additionally returning the number of attempts made.
+func (r *Retryable) retry(ctx context.Context, start time.Time, f func(context.Context) error) (int, error) {
+	if err := r.Validate(); err != nil {
+		return 0, err
+	}
+	b := r.backoff()
+	filter := r.ShouldRetry
+	if filter == nil {
+		filter = func(err error) bool {
+			return true
 		}
 	}
 
 	errors := &Errors{}
+	attempts := 0
 	for n := int32(0); n < r.MaxSteps; n++ {
-		err := f(ctx)
+		if r.Breaker != nil {
+			if err := r.Breaker.Allow(); err != nil {
+				return attempts, &CircuitOpenError{Errors: errors}
+			}
+		}
+		attempts++
+		if n == 0 && r.Budget != nil {
+			r.Budget.RecordAttempt()
+		}
+		if r.Metrics != nil {
+			r.Metrics.Attempt()
+		}
+		attemptCtx, span := r.tracer().Start(ctx, "retry.attempt")
+		span.SetAttributes(Attribute{Key: AttrAttempt, Value: attempts})
+		err := r.attempt(attemptCtx, f)
+		if r.Breaker != nil {
+			r.Breaker.Record(err)
+		}
 		if err == nil {
-			return nil
+			span.End()
+			return attempts, nil
+		}
+		span.RecordError(err)
+		if IsPermanent(err) {
+			span.End()
+			return attempts, unwrapPermanent(err)
 		}
 		if !filter(err) {
-			return err
+			span.End()
+			return attempts, err
 		}
-		errors.Errs = append(errors.Errs, &Error{
+		errors.add(&Error{
 			When: r.clock().Now(),
 			Err:  err,
-		})
-		nextStep := b.Next()
-		// Return immediately if the next step would step us beyond the
-		// deadline (as decided by the clock).
-		if beyondDeadline(nextStep) {
-			return &CtxErrors{
-				Errors: errors,
-				CtxErr: context.DeadlineExceeded,
-			}
+		}, r.ErrorRetention)
+		nextStep, stopErr := r.nextStep(ctx, b, start, err, errors)
+		if stopErr != nil {
+			span.End()
+			return attempts, stopErr
+		}
+		// We still back off after the final attempt, but that's not a
+		// retry, so it isn't reported as one.
+		retrying := n+1 < r.MaxSteps
+		// Or if the circuit breaker will still reject the next attempt
+		// after sleeping.
+		if retrying && r.Breaker != nil && !r.Breaker.allowsAt(r.clock().Now().Add(nextStep)) {
+			span.End()
+			return attempts, &CircuitOpenError{Errors: errors}
+		}
+		// Or if the retry budget shared with other Retryables is spent.
+		if retrying && r.Budget != nil && !r.Budget.TryRetry() {
+			span.End()
+			return attempts, err
+		}
+		if retrying {
+			span.SetAttributes(Attribute{Key: AttrBackoffDelay, Value: nextStep.Seconds()})
+		}
+		span.End()
+		if retrying && r.OnRetry != nil {
+			r.OnRetry(attempts, err, nextStep)
+		}
+		if retrying && r.Metrics != nil {
+			r.Metrics.Retry(nextStep)
 		}
 		if !r.clock().SleepFor(ctx, nextStep) {
-			return &CtxErrors{
-				Errors: errors,
-				CtxErr: ctx.Err(),
-			}
+			return attempts, newCtxErrors(ctx, errors)
+		}
+	}
+	return attempts, errors
+}
+
+// nextStep returns the interval to wait after an attempt that failed with err
+// (having been appended to errs), or an error to return instead of sleeping
+// if another attempt would be beyond the context's deadline or MaxElapsed.
+func (r *Retryable) nextStep(ctx context.Context, b BackoffStrategy, start time.Time, err error, errs *Errors) (time.Duration, error) {
+	nextStep := b.Next()
+	// Respect any hint from the server about how long to wait.
+	if hint := retryAfterHint(err); hint > nextStep {
+		nextStep = hint
+	}
+	// Return immediately if the next step would step us beyond the
+	// deadline (as decided by the clock).
+	if dl, ok := ctx.Deadline(); ok && r.clock().Until(dl) < nextStep {
+		return 0, &CtxErrors{
+			Errors: errs,
+			CtxErr: context.DeadlineExceeded,
 		}
 	}
-	return errors
+	// Likewise, if it would exhaust our own time budget.
+	if r.MaxElapsed > 0 && r.clock().Now().Sub(start)+nextStep > r.MaxElapsed {
+		return 0, &ElapsedErrors{
+			Errors:     errs,
+			MaxElapsed: r.MaxElapsed,
+		}
+	}
+	return nextStep, nil
 }
 
 // Retry calls the function `f` at most `steps` times using the exponential
@@ -122,6 +308,14 @@ func Retry(ctx context.Context, b Backoff, steps int, f func(context.Context) er
 	return r.Retry(ctx, f)
 }
 
+// RetryStrategy calls the function `f` at most `steps` times, waiting between
+// attempts for the intervals generated by `s`, or until the context expires.
+// `s` is cloned and reset before use, so it is not modified.
+func RetryStrategy(ctx context.Context, s BackoffStrategy, steps int, f func(context.Context) error) error {
+	r := Retryable{Strategy: s, MaxSteps: int32(steps), Clock: clocks.DefaultClock()}
+	return r.Retry(ctx, f)
+}
+
 // Error is an error that occurs at a particular time.
 type Error struct {
 	// When is when the error occured in the retry cycle.
@@ -138,17 +332,68 @@ func (e *Error) Unwrap() error {
 
 // Error implements the error interface.
 func (e *Error) Error() string {
-	return fmt.Sprintf("Error at %s: %s", e.When, e.Err.Error())
+	return fmt.Sprintf("Error at %s: %s", e.When.Format(errorsTimeFormat), e.Err.Error())
 }
 
 // Errors is a collection errors that happen across multiple retries.
 type Errors struct {
 	Errs []*Error
+
+	// Dropped is the number of errors that were discarded (according to
+	// Retryable.ErrorRetention) rather than kept in Errs.
+	Dropped int
 }
 
-// Error implements the error interface.
+// Error implements the error interface, returning the compact summary
+// described on Format.
 func (e *Errors) Error() string {
-	return fmt.Sprintf("errors retrying: %+v", e.Errs)
+	return fmt.Sprintf("%v", e)
+}
+
+// count returns the total number of errors added, including dropped ones.
+func (e *Errors) count() int {
+	return len(e.Errs) + e.Dropped
+}
+
+// add appends err to Errs, dropping an older error if that would keep more
+// than allowed by keep.
+func (e *Errors) add(err *Error, keep ErrorRetention) {
+	e.Errs = append(e.Errs, err)
+	first, last := keep.limits()
+	if !keep.bounded() || len(e.Errs) <= first+last {
+		return
+	}
+	// Drop the oldest error that isn't one of the first ones.
+	copy(e.Errs[first:], e.Errs[first+1:])
+	e.Errs[len(e.Errs)-1] = nil
+	e.Errs = e.Errs[:len(e.Errs)-1]
+	e.Dropped++
+}
+
+// ErrorRetention bounds the number of errors kept in Errors.Errs. The zero
+// value keeps every error.
+type ErrorRetention struct {
+	// First is the number of errors to keep from the start of the retry
+	// loop.
+	First int `json:"first"`
+	// Last is the number of most recent errors to keep. Setting only Last
+	// makes Errs a ring buffer of the most recent errors.
+	Last int `json:"last"`
+}
+
+func (k ErrorRetention) bounded() bool {
+	return k.First > 0 || k.Last > 0
+}
+
+// limits returns First and Last, treating negative values as 0.
+func (k ErrorRetention) limits() (first, last int) {
+	if k.First > 0 {
+		first = k.First
+	}
+	if k.Last > 0 {
+		last = k.Last
+	}
+	return first, last
 }
 
 // CtxErrors bundles together Errors and a Ctx error to differentiate the errors
@@ -157,4 +402,40 @@ func (e *Errors) Error() string {
 type CtxErrors struct {
 	*Errors
 	CtxErr error
+
+	// Cause is the cause of the context's cancellation, as reported by
+	// context.Cause, if that differs from CtxErr. It is always nil before
+	// go1.20.
+	Cause error
+}
+
+// Error implements the error interface, stating the context error (and
+// cause) before the compact summary of Errors.
+func (e *CtxErrors) Error() string {
+	return fmt.Sprintf("%v", e)
+}
+
+// newCtxErrors returns a CtxErrors for errs with ctx's error and cause.
+func newCtxErrors(ctx context.Context, errs *Errors) *CtxErrors {
+	e := &CtxErrors{
+		Errors: errs,
+		CtxErr: ctx.Err(),
+	}
+	if cause := contextCause(ctx); cause != nil && cause != e.CtxErr {
+		e.Cause = cause
+	}
+	return e
+}
+
+// ElapsedErrors bundles together Errors and the time budget that was exhausted
+// to differentiate errors that fail due to Retryable.MaxElapsed from context
+// expiration and from exhausting the maximum number of retries.
+type ElapsedErrors struct {
+	*Errors
+	MaxElapsed time.Duration
+}
+
+// Error implements the error interface.
+func (e *ElapsedErrors) Error() string {
+	return fmt.Sprintf("retry time budget of %s exhausted: %s", e.MaxElapsed, e.Errors.Error())
 }
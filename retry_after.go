@@ -0,0 +1,69 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryAfterer is implemented by errors carrying a hint from the server about
+// how long to wait before trying again (e.g. an HTTP 429 or 503 response's
+// Retry-After header).
+// When an error returned by the function passed to Retryable.Retry has a
+// RetryAfterer in its chain, the hint is used as a floor on the next backoff
+// interval.
+type RetryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+type retryAfterError struct {
+	err   error
+	after time.Duration
+}
+
+// WithRetryAfter wraps err so that it implements RetryAfterer, returning
+// after. WithRetryAfter returns nil if err is nil.
+func WithRetryAfter(err error, after time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, after: after}
+}
+
+// Error implements the error interface.
+func (r *retryAfterError) Error() string {
+	return r.err.Error()
+}
+
+// Unwrap follows go-1.13-style wrapping semantics.
+func (r *retryAfterError) Unwrap() error {
+	return r.err
+}
+
+// RetryAfter implements RetryAfterer.
+func (r *retryAfterError) RetryAfter() time.Duration {
+	return r.after
+}
+
+// retryAfterHint returns the hint carried by the first RetryAfterer in err's
+// chain, or 0 if there isn't one.
+func retryAfterHint(err error) time.Duration {
+	var ra RetryAfterer
+	if errors.As(err, &ra) {
+		return ra.RetryAfter()
+	}
+	return 0
+}
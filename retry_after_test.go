@@ -0,0 +1,99 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+func TestWithRetryAfter(t *testing.T) {
+	base := errors.New("throttled")
+	assert.Nil(t, WithRetryAfter(nil, time.Second))
+
+	err := fmt.Errorf("calling service: %w", WithRetryAfter(base, time.Second))
+	assert.True(t, errors.Is(err, base))
+	assert.Equal(t, time.Second, retryAfterHint(err))
+	assert.Zero(t, retryAfterHint(base))
+}
+
+func TestRetryableRetryAfterFloor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := make(chan struct{})
+	fc := fake.NewClock(time.Now())
+
+	r := NewRetryable(18)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	go func() {
+		q := 0
+		err := r.Retry(ctx, func(ctx context.Context) error {
+			q++
+			switch q {
+			case 1:
+				// hint larger than the backoff wins
+				return WithRetryAfter(errors.New("slow down"), time.Minute)
+			case 2:
+				// hint smaller than the backoff is ignored
+				return WithRetryAfter(errors.New("slow down"), time.Millisecond)
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, q)
+		close(c)
+	}()
+
+	fc.AwaitSleepers(1)
+	assert.Equal(t, []time.Time{fc.Now().Add(time.Minute)}, fc.Sleepers())
+	fc.Advance(time.Minute)
+	fc.AwaitSleepers(1)
+	assert.Equal(t, []time.Time{fc.Now().Add(time.Second)}, fc.Sleepers())
+	fc.Advance(time.Second)
+	<-c
+}
+
+func TestRetryableRetryAfterBeyondDeadline(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	ctx, cancel := context.WithDeadline(context.Background(), fc.Now().Add(10*time.Second))
+	defer cancel()
+
+	r := NewRetryable(18)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	q := 0
+	err := r.Retry(ctx, func(ctx context.Context) error {
+		q++
+		return WithRetryAfter(errors.New("slow down"), time.Minute)
+	})
+	// We should give up without sleeping, since the server asked us to wait
+	// past the deadline.
+	assert.Equal(t, 1, q)
+	theErr := &CtxErrors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Equal(t, context.DeadlineExceeded, theErr.CtxErr)
+	assert.Zero(t, fc.NumAggSleepers())
+}
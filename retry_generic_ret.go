@@ -19,6 +19,8 @@ package retry
 
 import (
 	"context"
+	"errors"
+	"sync"
 )
 
 // Typed provides a wrapper around the Retryable type that handles
@@ -36,3 +38,77 @@ func Typed[T any](ctx context.Context, r *Retryable, f func(context.Context) (T,
 	})
 	return ret, err
 }
+
+// HedgeTyped provides a wrapper around Retryable.Hedge that handles
+// arbitrary callback return-types in addition to an error. It returns the
+// value from the first successful call.
+func HedgeTyped[T any](ctx context.Context, r *Retryable, f func(context.Context) (T, error)) (T, error) {
+	var ret T
+	once := sync.Once{}
+
+	err := r.Hedge(ctx, func(ctx context.Context) error {
+		rv, callErr := f(ctx)
+		if callErr != nil {
+			return callErr
+		}
+		once.Do(func() { ret = rv })
+		return nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return ret, nil
+}
+
+// ErrRejectedResult is matched (with errors.Is) by the errors TypedUntil
+// records for results rejected by its predicate.
+var ErrRejectedResult = errors.New("result rejected")
+
+// RejectedResultError is recorded by TypedUntil for each result rejected by
+// its predicate.
+type RejectedResultError[T any] struct {
+	// Value is the rejected result.
+	Value T
+}
+
+// Error implements the error interface.
+func (e *RejectedResultError[T]) Error() string {
+	return ErrRejectedResult.Error()
+}
+
+// Is returns true for ErrRejectedResult.
+func (e *RejectedResultError[T]) Is(target error) bool {
+	return target == ErrRejectedResult
+}
+
+// TypedUntil is like Typed, but also retries when `f` succeeds with a value
+// for which `done` returns false (e.g. a job that is still pending).
+// Each rejected value is recorded in the returned Errors as a
+// *RejectedResultError[T]; rejected values are always retried, regardless of
+// the Retryable's ShouldRetry.
+// When giving up, TypedUntil returns the last value returned by `f` without
+// an error (if any), along with the error from Retry.
+func TypedUntil[T any](ctx context.Context, r *Retryable, f func(context.Context) (T, error), done func(T) bool) (T, error) {
+	var ret T
+
+	rc := *r
+	if filter := r.ShouldRetry; filter != nil {
+		rc.ShouldRetry = func(err error) bool {
+			var rejected *RejectedResultError[T]
+			return errors.As(err, &rejected) || filter(err)
+		}
+	}
+	err := rc.Retry(ctx, func(ctx context.Context) error {
+		rv, callErr := f(ctx)
+		if callErr != nil {
+			return callErr
+		}
+		ret = rv
+		if !done(rv) {
+			return &RejectedResultError[T]{Value: rv}
+		}
+		return nil
+	})
+	return ret, err
+}
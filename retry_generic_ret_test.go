@@ -19,11 +19,15 @@ package retry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/go-clocks/fake"
 )
 
 func TestTyped(t *testing.T) {
@@ -56,3 +60,79 @@ func TestTyped(t *testing.T) {
 	}()
 	<-c
 }
+
+func TestHedgeTyped(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(3)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	c := make(chan struct{})
+	var calls int32
+	go func() {
+		s, err := HedgeTyped(context.Background(), r, func(ctx context.Context) (string, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-ctx.Done()
+				return "slow", ctx.Err()
+			}
+			return "fast", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", s)
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	<-c
+}
+
+func TestTypedUntil(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := NewRetryable(18)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+	// rejected results are retried regardless of ShouldRetry
+	r.ShouldRetry = func(error) bool { return false }
+
+	q := 0
+	s, err := TypedUntil(ctx, r, func(ctx context.Context) (string, error) {
+		q++
+		if q < 3 {
+			return "PENDING", nil
+		}
+		return "DONE", nil
+	}, func(s string) bool { return s == "DONE" })
+	assert.NoError(t, err)
+	assert.Equal(t, "DONE", s)
+	assert.Equal(t, 3, q)
+}
+
+func TestTypedUntilGiveUp(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	r := NewRetryable(3)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+
+	q := 0
+	n, err := TypedUntil(ctx, r, func(ctx context.Context) (int, error) {
+		q++
+		if q == 3 {
+			return -1, fmt.Errorf("foo")
+		}
+		return q, nil
+	}, func(int) bool { return false })
+
+	// The last successfully returned value is returned.
+	assert.Equal(t, 2, n)
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	require.Len(t, theErr.Errs, 3)
+	for i, e := range theErr.Errs[:2] {
+		assert.True(t, errors.Is(e, ErrRejectedResult))
+		rejected := &RejectedResultError[int]{}
+		require.True(t, errors.As(e, &rejected))
+		assert.Equal(t, i+1, rejected.Value)
+	}
+	assert.False(t, errors.Is(theErr.Errs[2], ErrRejectedResult))
+}
@@ -0,0 +1,155 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+//go:build go1.23
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// errAttemptFailed is recorded for iterations of an Attempts loop that
+// neither break nor call Fail.
+var errAttemptFailed = errors.New("attempt failed")
+
+// Attempts drives a retry loop written as a for loop over All, for loops
+// that don't fit in a closure. See Retryable.Attempts.
+type Attempts struct {
+	r   *Retryable
+	ctx context.Context
+
+	failErr error
+	errs    *Errors
+	err     error
+}
+
+// Attempts returns an Attempts whose All method iterates over attempt
+// numbers, backing off according to `B` (or `Strategy`) between iterations,
+// until `MaxSteps` iterations have run or the context expires.
+// An iteration that ends without breaking out of the loop is a failed attempt;
+// the loop body should report its error with Fail. Breaking out of the loop
+// ends it successfully, unless Fail was called in that iteration, in which
+// case the loop stops on that (non-retryable) error.
+//
+//	a := r.Attempts(ctx)
+//	for n := range a.All() {
+//		if err := op(n); err != nil {
+//			a.Fail(err)
+//			if isFatal(err) {
+//				break
+//			}
+//			continue
+//		}
+//		break
+//	}
+//	if err := a.Err(); err != nil {
+//		// handle the *Errors or *CtxErrors
+//	}
+//
+// MaxElapsed, Budget, retry-after hints and OnRetry are honored as by Retry;
+// ShouldRetry is not consulted, since the loop body can simply break, and the
+// other hooks are not called. Like Retry, All backs off after the final
+// attempt too, so Err is a *CtxErrors or *ElapsedErrors rather than *Errors
+// if that wait would run past the context's deadline or MaxElapsed; OnRetry
+// and Budget are only consulted before another attempt.
+//
+// Attempts returns a handle rather than an iter.Seq itself, since the loop
+// body needs somewhere to report errors (Fail), and the caller somewhere to
+// find them once the loop is over (Err and Errors).
+func (r *Retryable) Attempts(ctx context.Context) *Attempts {
+	return &Attempts{r: r, ctx: ctx}
+}
+
+// All returns an iterator over 1-indexed attempt numbers.
+func (a *Attempts) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		r := a.r
+		a.errs = &Errors{}
+		a.err = nil
+		if err := r.Validate(); err != nil {
+			a.err = err
+			return
+		}
+		b := r.backoff()
+		start := r.clock().Now()
+		for n := int32(1); n <= r.MaxSteps; n++ {
+			if n == 1 && r.Budget != nil {
+				r.Budget.RecordAttempt()
+			}
+			a.failErr = nil
+			if !yield(int(n)) {
+				// Breaking out after Fail stops on that error.
+				if a.failErr != nil {
+					a.errs.add(&Error{
+						When: r.clock().Now(),
+						Err:  a.failErr,
+					}, r.ErrorRetention)
+					a.err = unwrapPermanent(a.failErr)
+				}
+				return
+			}
+			err := a.failErr
+			if err == nil {
+				err = errAttemptFailed
+			}
+			a.errs.add(&Error{
+				When: r.clock().Now(),
+				Err:  err,
+			}, r.ErrorRetention)
+			nextStep, stopErr := r.nextStep(a.ctx, b, start, err, a.errs)
+			if stopErr != nil {
+				a.err = stopErr
+				return
+			}
+			// As in Retry, the backoff after the final attempt isn't a
+			// retry.
+			retrying := n < r.MaxSteps
+			if retrying && r.Budget != nil && !r.Budget.TryRetry() {
+				a.err = err
+				return
+			}
+			if retrying && r.OnRetry != nil {
+				r.OnRetry(int(n), err, nextStep)
+			}
+			if !r.clock().SleepFor(a.ctx, nextStep) {
+				a.err = newCtxErrors(a.ctx, a.errs)
+				return
+			}
+		}
+		a.err = a.errs
+	}
+}
+
+// Fail records err as the reason the current attempt failed.
+func (a *Attempts) Fail(err error) {
+	a.failErr = err
+}
+
+// Errors returns the errors from failed attempts so far.
+func (a *Attempts) Errors() *Errors {
+	return a.errs
+}
+
+// Err returns nil if the loop ended by breaking out of it, the error passed
+// to Fail (unwrapped, if marked Permanent) if it broke out after calling Fail,
+// or otherwise the error explaining why it stopped: an *Errors if every
+// attempt failed, a
+// *CtxErrors if the context expired, an *ElapsedErrors if MaxElapsed would
+// have been exceeded, or the last attempt's error if Budget was spent.
+func (a *Attempts) Err() error {
+	return a.err
+}
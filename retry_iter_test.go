@@ -0,0 +1,179 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+//go:build go1.23
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vimeo/go-clocks/fake"
+)
+
+func TestAttemptsSuccess(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(5)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}
+
+	c := make(chan struct{})
+	seen := []int{}
+	a := r.Attempts(context.Background())
+	go func() {
+		for n := range a.All() {
+			seen = append(seen, n)
+			if n < 3 {
+				a.Fail(fmt.Errorf("fail %d", n))
+				continue
+			}
+			break
+		}
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Second)
+	<-c
+
+	assert.Equal(t, []int{1, 2, 3}, seen)
+	assert.NoError(t, a.Err())
+	require.Len(t, a.Errors().Errs, 2)
+	assert.EqualError(t, a.Errors().Errs[1].Err, "fail 2")
+}
+
+func TestAttemptsExhausted(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(3)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+
+	a := r.Attempts(context.Background())
+	calls := 0
+	for n := range a.All() {
+		calls++
+		// Only report an error on the first attempt
+		if n == 1 {
+			a.Fail(errors.New("foo"))
+		}
+	}
+	assert.Equal(t, 3, calls)
+	theErr := &Errors{}
+	require.True(t, errors.As(a.Err(), &theErr))
+	require.Len(t, theErr.Errs, 3)
+	assert.EqualError(t, theErr.Errs[0].Err, "foo")
+	assert.Equal(t, errAttemptFailed, theErr.Errs[2].Err)
+}
+
+func TestAttemptsCtxCancel(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewRetryable(5)
+	r.Clock = fc
+
+	a := r.Attempts(ctx)
+	c := make(chan struct{})
+	go func() {
+		for range a.All() {
+			a.Fail(errors.New("foo"))
+		}
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	cancel()
+	<-c
+	theErr := &CtxErrors{}
+	require.True(t, errors.As(a.Err(), &theErr))
+	assert.Equal(t, context.Canceled, theErr.CtxErr)
+	assert.Len(t, theErr.Errs, 1)
+}
+
+func TestAttemptsFailAndBreak(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(5)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+
+	fatal := errors.New("fatal")
+	a := r.Attempts(context.Background())
+	calls := 0
+	for n := range a.All() {
+		calls++
+		if n < 2 {
+			a.Fail(errors.New("transient"))
+			continue
+		}
+		a.Fail(Permanent(fatal))
+		break
+	}
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, fatal, a.Err())
+	require.Len(t, a.Errors().Errs, 2)
+	assert.True(t, IsPermanent(a.Errors().Errs[1].Err))
+}
+
+func TestAttemptsFinalBackoffMatchesRetry(t *testing.T) {
+	t.Parallel()
+	// run drives loop with a Retryable making 2 attempts an hour apart,
+	// and a deadline that leaves time for the backoff after the first
+	// attempt, but not for the one after the final attempt.
+	run := func(loop func(ctx context.Context, r *Retryable) error) (int, error) {
+		fc := fake.NewClock(time.Now())
+		retries := 0
+		r := NewRetryable(2)
+		r.Clock = fc
+		r.B = Backoff{MinBackoff: time.Hour, MaxBackoff: time.Hour}
+		r.OnRetry = func(int, error, time.Duration) { retries++ }
+		ctx, cancel := context.WithDeadline(context.Background(), fc.Now().Add(90*time.Minute))
+		defer cancel()
+
+		c := make(chan error)
+		go func() {
+			c <- loop(ctx, r)
+		}()
+		fc.AwaitSleepers(1)
+		fc.Advance(time.Hour)
+		err := <-c
+		return retries, err
+	}
+
+	retryRetries, retryErr := run(func(ctx context.Context, r *Retryable) error {
+		return r.Retry(ctx, func(ctx context.Context) error {
+			return errors.New("foo")
+		})
+	})
+	attemptsRetries, attemptsErr := run(func(ctx context.Context, r *Retryable) error {
+		a := r.Attempts(ctx)
+		for range a.All() {
+			a.Fail(errors.New("foo"))
+		}
+		return a.Err()
+	})
+
+	for _, err := range []error{retryErr, attemptsErr} {
+		theErr := &CtxErrors{}
+		require.True(t, errors.As(err, &theErr))
+		assert.Equal(t, context.DeadlineExceeded, theErr.CtxErr)
+		assert.Len(t, theErr.Errs, 2)
+	}
+	assert.Equal(t, 1, retryRetries)
+	assert.Equal(t, 1, attemptsRetries)
+}
@@ -0,0 +1,72 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+//go:build go1.21
+
+package retry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Attribute keys used in records logged by a Retryable with a logger
+// attached by SetLogger.
+const (
+	LogKeyAttempt  = "attempt"
+	LogKeyMaxSteps = "max_steps"
+	LogKeyDelay    = "delay"
+	LogKeyError    = "error"
+	LogKeyElapsed  = "elapsed"
+	LogKeyOutcome  = "outcome"
+)
+
+// SetLogger sets the OnRetry, OnSuccess and OnGiveUp hooks on r to log
+// structured records to logger: failed attempts (with the delay before the
+// next one) at Info, success at Debug (Info if it took more than one
+// attempt), and giving up at Error. Hooks already set on r are still called
+// (before logging).
+func (r *Retryable) SetLogger(logger *slog.Logger) {
+	onRetry, onSuccess, onGiveUp := r.OnRetry, r.OnSuccess, r.OnGiveUp
+
+	r.OnRetry = func(attempt int, err error, delay time.Duration) {
+		if onRetry != nil {
+			onRetry(attempt, err, delay)
+		}
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "attempt failed; retrying",
+			slog.Int(LogKeyAttempt, attempt), slog.Int(LogKeyMaxSteps, int(r.MaxSteps)),
+			slog.Duration(LogKeyDelay, delay), slog.Any(LogKeyError, err))
+	}
+	r.OnSuccess = func(attempts int, elapsed time.Duration) {
+		if onSuccess != nil {
+			onSuccess(attempts, elapsed)
+		}
+		lvl := slog.LevelDebug
+		if attempts > 1 {
+			lvl = slog.LevelInfo
+		}
+		logger.LogAttrs(context.Background(), lvl, "attempt succeeded",
+			slog.Int(LogKeyAttempt, attempts), slog.Int(LogKeyMaxSteps, int(r.MaxSteps)),
+			slog.Duration(LogKeyElapsed, elapsed), slog.String(LogKeyOutcome, OutcomeSuccess.String()))
+	}
+	r.OnGiveUp = func(attempts int, err error) {
+		if onGiveUp != nil {
+			onGiveUp(attempts, err)
+		}
+		logger.LogAttrs(context.Background(), slog.LevelError, "giving up",
+			slog.Int(LogKeyAttempt, attempts), slog.Int(LogKeyMaxSteps, int(r.MaxSteps)),
+			slog.String(LogKeyOutcome, outcomeOf(err).String()), slog.Any(LogKeyError, err))
+	}
+}
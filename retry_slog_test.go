@@ -0,0 +1,113 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+//go:build go1.21
+
+package retry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	out := []map[string]interface{}{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		rec := map[string]interface{}{}
+		require.NoError(t, dec.Decode(&rec))
+		out = append(out, rec)
+	}
+	return out
+}
+
+func TestRetryableSetLogger(t *testing.T) {
+	t.Parallel()
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	r := NewRetryable(3)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+	giveUps := 0
+	r.OnGiveUp = func(int, error) { giveUps++ }
+	r.SetLogger(logger)
+
+	err := r.Retry(context.Background(), func(ctx context.Context) error {
+		return fmt.Errorf("foo")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, giveUps, "pre-existing hook not called")
+
+	recs := decodeLogRecords(t, &buf)
+	require.Len(t, recs, 3)
+	for i, rec := range recs[:2] {
+		assert.Equal(t, "INFO", rec[slog.LevelKey])
+		assert.EqualValues(t, i+1, rec[LogKeyAttempt])
+		assert.EqualValues(t, 3, rec[LogKeyMaxSteps])
+		assert.EqualValues(t, time.Microsecond, rec[LogKeyDelay])
+		assert.Equal(t, "foo", rec[LogKeyError])
+	}
+	assert.Equal(t, "ERROR", recs[2][slog.LevelKey])
+	assert.EqualValues(t, 3, recs[2][LogKeyAttempt])
+	assert.Equal(t, "exhausted", recs[2][LogKeyOutcome])
+	assert.Equal(t, err.Error(), recs[2][LogKeyError])
+}
+
+func TestRetryableSetLoggerSuccess(t *testing.T) {
+	t.Parallel()
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	r := NewRetryable(3)
+	r.SetLogger(logger)
+	require.NoError(t, r.Retry(context.Background(), func(ctx context.Context) error {
+		return nil
+	}))
+
+	recs := decodeLogRecords(t, &buf)
+	require.Len(t, recs, 1)
+	assert.Equal(t, "DEBUG", recs[0][slog.LevelKey])
+	assert.EqualValues(t, 1, recs[0][LogKeyAttempt])
+	assert.Equal(t, "success", recs[0][LogKeyOutcome])
+}
+
+func TestRetryableSetLoggerInvalidBackoff(t *testing.T) {
+	t.Parallel()
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := NewRetryable(3)
+	r.B.MinBackoff = time.Hour
+	r.B.MaxBackoff = time.Second
+	r.SetLogger(logger)
+	err := r.Retry(context.Background(), func(ctx context.Context) error {
+		t.Error("should not be called with an invalid backoff")
+		return nil
+	})
+	require.Error(t, err)
+
+	recs := decodeLogRecords(t, &buf)
+	require.Len(t, recs, 1)
+	assert.Equal(t, "aborted", recs[0][LogKeyOutcome])
+	assert.Equal(t, "invalid retryable: MinBackoff (1h0m0s) > MaxBackoff (1s)", recs[0][LogKeyError])
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -186,6 +188,58 @@ func TestRetriableWithFakeClock(t *testing.T) {
 	<-c
 }
 
+type constantStrategy struct {
+	d     time.Duration
+	calls int
+}
+
+func (c *constantStrategy) Next() time.Duration {
+	c.calls++
+	return c.d
+}
+
+func (c *constantStrategy) Reset() {
+	c.calls = 0
+}
+
+func (c *constantStrategy) CloneStrategy() BackoffStrategy {
+	cl := *c
+	return &cl
+}
+
+func TestRetryableWithStrategy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := make(chan struct{})
+	fc := fake.NewClock(time.Now())
+	strat := &constantStrategy{d: time.Hour}
+
+	go func() {
+		q := 0
+		r := NewRetryable(18)
+		r.Clock = fc
+		r.Strategy = strat
+		err := r.Retry(ctx, func(ctx context.Context) error {
+			q++
+			if q == 3 {
+				return nil
+			}
+			return fmt.Errorf("foo")
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, q)
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	assert.Equal(t, []time.Time{fc.Now().Add(time.Hour)}, fc.Sleepers())
+	assert.EqualValues(t, 1, fc.Advance(time.Hour))
+	fc.AwaitSleepers(1)
+	assert.EqualValues(t, 1, fc.Advance(time.Hour))
+	<-c
+	// The Retryable's strategy must be cloned, not mutated.
+	assert.Zero(t, strat.calls)
+}
+
 func TestErrorsWrapping(t *testing.T) {
 	last := errors.New("this should get unwrapped")
 	errs := &Errors{
@@ -240,3 +294,346 @@ func TestErrorsAs(t *testing.T) {
 	require.True(t, errors.As(errs, &err))
 	assert.Equal(t, 42, err.magicNum)
 }
+
+func TestRetryableNewRandConcurrent(t *testing.T) {
+	t.Parallel()
+	r := NewRetryable(5)
+	r.B.MinBackoff = time.Microsecond
+	r.B.MaxBackoff = time.Millisecond
+	// *rand.Rand isn't thread-safe; each call to Retry gets its own.
+	r.B.NewRand = func() RandSource { return rand.New(rand.NewSource(1)) }
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := r.Retry(context.Background(), func(ctx context.Context) error {
+				return errors.New("foo")
+			})
+			assert.Error(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRetryStrategyDecorrelatedJitter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s := DefaultDecorrelatedJitter()
+	s.MinBackoff = time.Microsecond
+	s.MaxBackoff = time.Millisecond
+
+	q := 0
+	err := RetryStrategy(ctx, &s, 8, func(ctx context.Context) error {
+		q++
+		return fmt.Errorf("foo")
+	})
+
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Len(t, theErr.Errs, 8)
+	assert.Equal(t, 8, q)
+	// the strategy passed in should not have been advanced.
+	assert.Zero(t, s.prev)
+}
+
+func TestRetryableMaxElapsed(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := make(chan struct{})
+	fc := fake.NewClock(time.Now())
+
+	r := NewRetryable(80)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	r.MaxElapsed = time.Hour*2 + time.Minute*30
+
+	go func() {
+		q := 0
+		err := r.Retry(ctx, func(ctx context.Context) error {
+			q++
+			return fmt.Errorf("foo")
+		})
+
+		theErr := &ElapsedErrors{}
+		require.True(t, errors.As(err, &theErr))
+		assert.Equal(t, r.MaxElapsed, theErr.MaxElapsed)
+		assert.Len(t, theErr.Errs, 3)
+		assert.Equal(t, 3, q)
+		assert.False(t, errors.As(err, new(*CtxErrors)))
+		close(c)
+	}()
+
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Hour)
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Hour)
+	// The third attempt fails 2h after the first, and another hour of
+	// backoff would exceed the 2.5h budget.
+	<-c
+}
+
+func TestRetryableAttemptTimeout(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := make(chan struct{})
+	fc := fake.NewClock(time.Now())
+
+	r := NewRetryable(3)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	r.AttemptTimeout = time.Minute
+
+	go func() {
+		q := 0
+		err := r.Retry(ctx, func(ctx context.Context) error {
+			q++
+			if q == 2 {
+				return nil
+			}
+			// hang until the attempt times out
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, q)
+		close(c)
+	}()
+
+	// first attempt hangs until its deadline
+	fc.AwaitSleepers(1)
+	assert.Equal(t, []time.Time{fc.Now().Add(time.Minute)}, fc.Sleepers())
+	fc.Advance(time.Minute)
+	// then we back off
+	fc.AwaitSleepers(1)
+	assert.Equal(t, []time.Time{fc.Now().Add(time.Hour)}, fc.Sleepers())
+	fc.Advance(time.Hour)
+	<-c
+}
+
+func TestRetryableAttemptTimeoutRecorded(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := make(chan struct{})
+	fc := fake.NewClock(time.Now())
+
+	r := NewRetryable(1)
+	r.Clock = fc
+	r.AttemptTimeout = time.Minute
+
+	go func() {
+		err := r.Retry(ctx, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		theErr := &Errors{}
+		require.True(t, errors.As(err, &theErr))
+		require.Len(t, theErr.Errs, 1)
+		assert.True(t, errors.Is(theErr.Errs[0], context.DeadlineExceeded))
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Minute)
+	// Retry backs off after the final attempt as well.
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Minute)
+	<-c
+}
+
+func TestRetryableHooks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := make(chan struct{})
+	fc := fake.NewClock(time.Now())
+
+	type retryCall struct {
+		attempt int
+		err     error
+		delay   time.Duration
+	}
+	retries := []retryCall{}
+	successAttempts := 0
+	var successElapsed time.Duration
+
+	r := NewRetryable(18)
+	r.Clock = fc
+	r.B = Backoff{MinBackoff: time.Hour, MaxBackoff: time.Hour}
+	r.OnRetry = func(attempt int, err error, delay time.Duration) {
+		retries = append(retries, retryCall{attempt: attempt, err: err, delay: delay})
+	}
+	r.OnSuccess = func(attempts int, elapsed time.Duration) {
+		successAttempts = attempts
+		successElapsed = elapsed
+	}
+	r.OnGiveUp = func(int, error) {
+		t.Error("unexpected call to OnGiveUp")
+	}
+
+	fooErr := errors.New("foo")
+	go func() {
+		q := 0
+		err := r.Retry(ctx, func(ctx context.Context) error {
+			q++
+			if q == 3 {
+				return nil
+			}
+			return fooErr
+		})
+		assert.NoError(t, err)
+		close(c)
+	}()
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Hour)
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Hour)
+	<-c
+
+	assert.Equal(t, []retryCall{
+		{attempt: 1, err: fooErr, delay: time.Hour},
+		{attempt: 2, err: fooErr, delay: time.Hour},
+	}, retries)
+	assert.Equal(t, 3, successAttempts)
+	assert.Equal(t, 2*time.Hour, successElapsed)
+}
+
+func TestRetryableOnGiveUp(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	retries := 0
+	giveUpAttempts := 0
+	var giveUpErr error
+
+	r := NewRetryable(4)
+	r.B.MinBackoff = time.Microsecond
+	r.OnRetry = func(int, error, time.Duration) {
+		retries++
+	}
+	r.OnSuccess = func(int, time.Duration) {
+		t.Error("unexpected call to OnSuccess")
+	}
+	r.OnGiveUp = func(attempts int, err error) {
+		giveUpAttempts = attempts
+		giveUpErr = err
+	}
+
+	err := r.Retry(ctx, func(ctx context.Context) error {
+		return fmt.Errorf("foo")
+	})
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Len(t, theErr.Errs, 4)
+	assert.Equal(t, err, giveUpErr)
+	assert.Equal(t, 4, giveUpAttempts)
+	// No retry follows the final attempt.
+	assert.Equal(t, 3, retries)
+}
+
+func TestRetryableSingleStepNoRetry(t *testing.T) {
+	t.Parallel()
+	fc := fake.NewClock(time.Now())
+	r := NewRetryable(1)
+	r.B = Backoff{MinBackoff: time.Minute, MaxBackoff: time.Minute}
+	r.Clock = fc
+	r.OnRetry = func(int, error, time.Duration) {
+		t.Error("OnRetry called with no attempts left")
+	}
+
+	c := make(chan error)
+	go func() {
+		c <- r.Retry(context.Background(), func(ctx context.Context) error {
+			return fmt.Errorf("foo")
+		})
+	}()
+	// The final attempt is still followed by a backoff.
+	fc.AwaitSleepers(1)
+	fc.Advance(time.Minute)
+	err := <-c
+	theErr := &Errors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.Len(t, theErr.Errs, 1)
+}
+
+func TestRetryInvalidBackoff(t *testing.T) {
+	t.Parallel()
+	b := DefaultBackoff()
+	b.MinBackoff = b.MaxBackoff * 2
+	err := Retry(context.Background(), b, 3, func(ctx context.Context) error {
+		t.Error("should not be called with an invalid backoff")
+		return nil
+	})
+	assert.EqualError(t, err, "invalid retryable: MinBackoff (2m0s) > MaxBackoff (1m0s)")
+}
+
+func TestRetryableErrorRetention(t *testing.T) {
+	t.Parallel()
+	sentinel := errors.New("first")
+	for _, tc := range []struct {
+		name string
+		keep ErrorRetention
+		want []string
+	}{
+		{name: "unbounded", keep: ErrorRetention{}, want: []string{"first", "2", "3", "4", "5", "6"}},
+		{name: "first_and_last", keep: ErrorRetention{First: 1, Last: 2}, want: []string{"first", "5", "6"}},
+		{name: "ring", keep: ErrorRetention{Last: 2}, want: []string{"5", "6"}},
+		{name: "first_only", keep: ErrorRetention{First: 2}, want: []string{"first", "2"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := NewRetryable(6)
+			r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+			r.ErrorRetention = tc.keep
+			calls := 0
+			err := r.Retry(context.Background(), func(ctx context.Context) error {
+				calls++
+				if calls == 1 {
+					return sentinel
+				}
+				return fmt.Errorf("%d", calls)
+			})
+			theErr := &Errors{}
+			require.True(t, errors.As(err, &theErr))
+			got := make([]string, len(theErr.Errs))
+			for i, e := range theErr.Errs {
+				got[i] = e.Err.Error()
+			}
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, 6-len(tc.want), theErr.Dropped)
+			if theErr.Dropped > 0 {
+				assert.Contains(t, err.Error(), fmt.Sprintf("(%d dropped)", theErr.Dropped))
+			}
+			assert.Equal(t, tc.want[0] == "first", errors.Is(err, sentinel))
+		})
+	}
+}
+
+func TestRetryCtxErrorsUnwrap(t *testing.T) {
+	t.Parallel()
+	fooErr := errors.New("foo")
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Retry(ctx, DefaultBackoff(), 3, func(ctx context.Context) error {
+		cancel()
+		return fooErr
+	})
+	theErr := &CtxErrors{}
+	require.True(t, errors.As(err, &theErr))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, fooErr))
+	assert.False(t, errors.Is(err, context.DeadlineExceeded))
+	attemptErr := &Error{}
+	require.True(t, errors.As(err, &attemptErr))
+	assert.Equal(t, fooErr, attemptErr.Err)
+	assert.Regexp(t, `^retrying aborted by context: context canceled: errors retrying: 1 error at .*: foo$`, err.Error())
+
+	// The deadline is checked before sleeping, while the context is live.
+	dctx, dcancel := context.WithTimeout(context.Background(), time.Hour)
+	defer dcancel()
+	b := Backoff{MinBackoff: 2 * time.Hour, MaxBackoff: 2 * time.Hour}
+	err = Retry(dctx, b, 3, func(ctx context.Context) error {
+		return fooErr
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, errors.Is(err, fooErr))
+}
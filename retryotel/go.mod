@@ -0,0 +1,22 @@
+module github.com/vimeo/go-retry/retryotel
+
+go 1.20
+
+require (
+	github.com/vimeo/go-retry v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/sdk v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/vimeo/go-clocks v1.0.0 // indirect
+	go.opentelemetry.io/otel/metric v1.24.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+)
+
+// Build against the parent module in this repository until a release
+// including the Tracer API is tagged, then require that release instead.
+replace github.com/vimeo/go-retry => ../
@@ -0,0 +1,85 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+// Package retryotel adapts OpenTelemetry tracers for use as a
+// github.com/vimeo/go-retry.Tracer.
+package retryotel
+
+import (
+	"context"
+	"fmt"
+
+	retry "github.com/vimeo/go-retry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer wraps an OpenTelemetry trace.Tracer to implement retry.Tracer.
+type Tracer struct {
+	T trace.Tracer
+}
+
+var _ retry.Tracer = Tracer{}
+
+// NewTracer returns a retry.Tracer that starts spans with t.
+func NewTracer(t trace.Tracer) Tracer {
+	return Tracer{T: t}
+}
+
+// Start implements retry.Tracer
+func (t Tracer) Start(ctx context.Context, name string) (context.Context, retry.Span) {
+	ctx, s := t.T.Start(ctx, name)
+	return ctx, span{s: s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+func (s span) SetAttributes(attrs ...retry.Attribute) {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kvs = append(kvs, convertAttribute(a))
+	}
+	s.s.SetAttributes(kvs...)
+}
+
+func (s span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.s.End()
+}
+
+// convertAttribute converts a retry.Attribute to its OpenTelemetry
+// equivalent, falling back to a string for unexpected value types.
+func convertAttribute(a retry.Attribute) attribute.KeyValue {
+	switch v := a.Value.(type) {
+	case bool:
+		return attribute.Bool(a.Key, v)
+	case int:
+		return attribute.Int(a.Key, v)
+	case int64:
+		return attribute.Int64(a.Key, v)
+	case float64:
+		return attribute.Float64(a.Key, v)
+	case string:
+		return attribute.String(a.Key, v)
+	default:
+		return attribute.String(a.Key, fmt.Sprint(v))
+	}
+}
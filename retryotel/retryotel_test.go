@@ -0,0 +1,102 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retryotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	retry "github.com/vimeo/go-retry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+
+	r := retry.NewRetryable(2)
+	r.B = retry.Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+	r.Tracer = NewTracer(tp.Tracer("retryotel-test"))
+
+	q := 0
+	err := r.Retry(context.Background(), func(ctx context.Context) error {
+		q++
+		if q == 1 {
+			return errors.New("foo")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("unexpected number of spans: %d", len(spans))
+	}
+	// spans are recorded in the order they end
+	first, second, root := spans[0], spans[1], spans[2]
+	if root.Name() != "retry" || first.Name() != "retry.attempt" || second.Name() != "retry.attempt" {
+		t.Fatalf("unexpected span names: %q, %q, %q", root.Name(), first.Name(), second.Name())
+	}
+	for _, s := range []sdktrace.ReadOnlySpan{first, second} {
+		if s.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("attempt span %v is not a child of the root span", s.Attributes())
+		}
+	}
+	if first.Status().Code != codes.Error || first.Status().Description != "foo" {
+		t.Errorf("unexpected status on failed attempt: %+v", first.Status())
+	}
+	if second.Status().Code != codes.Unset {
+		t.Errorf("unexpected status on successful attempt: %+v", second.Status())
+	}
+
+	wantFirst := map[attribute.Key]attribute.Value{
+		retry.AttrAttempt:      attribute.IntValue(1),
+		retry.AttrBackoffDelay: attribute.Float64Value(time.Microsecond.Seconds()),
+	}
+	for _, kv := range first.Attributes() {
+		if want, ok := wantFirst[kv.Key]; ok && want != kv.Value {
+			t.Errorf("attribute %s = %v; want %v", kv.Key, kv.Value.Emit(), want.Emit())
+		}
+		delete(wantFirst, kv.Key)
+	}
+	if len(wantFirst) > 0 {
+		t.Errorf("missing attributes on first attempt span: %v", wantFirst)
+	}
+}
+
+func TestConvertAttribute(t *testing.T) {
+	for _, tbl := range []struct {
+		in   retry.Attribute
+		want attribute.KeyValue
+	}{
+		{retry.Attribute{Key: "b", Value: true}, attribute.Bool("b", true)},
+		{retry.Attribute{Key: "i", Value: 3}, attribute.Int("i", 3)},
+		{retry.Attribute{Key: "i64", Value: int64(4)}, attribute.Int64("i64", 4)},
+		{retry.Attribute{Key: "f", Value: 1.5}, attribute.Float64("f", 1.5)},
+		{retry.Attribute{Key: "s", Value: "x"}, attribute.String("s", "x")},
+		{retry.Attribute{Key: "d", Value: time.Second}, attribute.String("d", "1s")},
+	} {
+		if got := convertAttribute(tbl.in); got != tbl.want {
+			t.Errorf("convertAttribute(%+v) = %+v; want %+v", tbl.in, got, tbl.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracer starts spans. It is a minimal subset of OpenTelemetry's
+// trace.Tracer, so this package needn't depend on OpenTelemetry; see the
+// github.com/vimeo/go-retry/retryotel module for an adapter.
+//
+// When Retryable.Tracer is set, Retry starts a span named "retry" covering
+// the whole call, and a child span named "retry.attempt" for each attempt.
+type Tracer interface {
+	// Start starts a span that is a child of any span in ctx, returning a
+	// context containing the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by a Tracer.
+type Span interface {
+	// SetAttributes sets attributes on the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError records err as having occurred during the span, and
+	// marks the span as failed.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// Attribute is a key-value pair attached to a Span. Value is one of bool,
+// int, int64, float64 or string.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attribute keys set on spans started by Retryable.Retry.
+const (
+	// AttrMaxSteps is the Retryable's MaxSteps (on the "retry" span)
+	AttrMaxSteps = "retry.max_steps"
+	// AttrAttempts is the number of attempts made (on the "retry" span)
+	AttrAttempts = "retry.attempts"
+	// AttrOutcome is the Outcome's String() (on the "retry" span)
+	AttrOutcome = "retry.outcome"
+	// AttrAttempt is the 1-indexed attempt number (on "retry.attempt"
+	// spans)
+	AttrAttempt = "retry.attempt"
+	// AttrBackoffDelay is the delay in seconds before the next attempt, if
+	// there is one (on "retry.attempt" spans)
+	AttrBackoffDelay = "retry.backoff_delay_seconds"
+)
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// RecordingTracer is a Tracer that records spans in memory, for use in
+// tests. It is safe for concurrent use.
+type RecordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+// RecordedSpan is a snapshot of a span started by a RecordingTracer.
+type RecordedSpan struct {
+	// ID is the span's index in the output of RecordingTracer.Spans.
+	ID int
+	// ParentID is the ID of the span's parent, or -1 if it is a root span.
+	ParentID   int
+	Name       string
+	Attributes []Attribute
+	Errors     []error
+	Ended      bool
+}
+
+type recordingSpan struct {
+	t *RecordingTracer
+	RecordedSpan
+}
+
+type recordingSpanKey struct{}
+
+// Start implements Tracer
+func (t *RecordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parentID := -1
+	if p, ok := ctx.Value(recordingSpanKey{}).(*recordingSpan); ok && p.t == t {
+		parentID = p.ID
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &recordingSpan{t: t, RecordedSpan: RecordedSpan{
+		ID:       len(t.spans),
+		ParentID: parentID,
+		Name:     name,
+	}}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, recordingSpanKey{}, s), s
+}
+
+// Spans returns snapshots of all spans started so far, in the order they
+// were started.
+func (t *RecordingTracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]RecordedSpan, len(t.spans))
+	for i, s := range t.spans {
+		out[i] = s.RecordedSpan
+		out[i].Attributes = append([]Attribute(nil), s.Attributes...)
+		out[i].Errors = append([]error(nil), s.Errors...)
+	}
+	return out
+}
+
+// Attr returns the value of the last attribute with the given key, and
+// whether there was one.
+func (s *RecordedSpan) Attr(key string) (interface{}, bool) {
+	for i := len(s.Attributes) - 1; i >= 0; i-- {
+		if s.Attributes[i].Key == key {
+			return s.Attributes[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...Attribute) {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.Attributes = append(s.Attributes, attrs...)
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.Errors = append(s.Errors, err)
+}
+
+func (s *recordingSpan) End() {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.Ended = true
+}
@@ -0,0 +1,110 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingTracerParents(t *testing.T) {
+	tr := &RecordingTracer{}
+	ctx, root := tr.Start(context.Background(), "root")
+	_, child := tr.Start(ctx, "child")
+	child.SetAttributes(Attribute{Key: "k", Value: 1}, Attribute{Key: "k", Value: 2})
+	child.End()
+	_, other := (&RecordingTracer{}).Start(ctx, "other tracer")
+	other.End()
+
+	spans := tr.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, -1, spans[0].ParentID)
+	assert.False(t, spans[0].Ended)
+	assert.Equal(t, 0, spans[1].ParentID)
+	assert.True(t, spans[1].Ended)
+	v, ok := spans[1].Attr("k")
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	root.End()
+}
+
+func TestRetryableTracer(t *testing.T) {
+	t.Parallel()
+	tr := &RecordingTracer{}
+	r := NewRetryable(3)
+	r.B = Backoff{MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond}
+	r.Tracer = tr
+
+	fooErr := errors.New("foo")
+	err := r.Retry(context.Background(), func(ctx context.Context) error {
+		return fooErr
+	})
+	require.Error(t, err)
+
+	spans := tr.Spans()
+	require.Len(t, spans, 4)
+	root := spans[0]
+	assert.Equal(t, "retry", root.Name)
+	assert.Equal(t, -1, root.ParentID)
+	assert.True(t, root.Ended)
+	assert.Equal(t, []error{err}, root.Errors)
+	for key, val := range map[string]interface{}{
+		AttrMaxSteps: 3,
+		AttrAttempts: 3,
+		AttrOutcome:  "exhausted",
+	} {
+		v, ok := root.Attr(key)
+		assert.True(t, ok, key)
+		assert.Equal(t, val, v, key)
+	}
+
+	for i, s := range spans[1:] {
+		assert.Equal(t, "retry.attempt", s.Name)
+		assert.Equal(t, 0, s.ParentID)
+		assert.True(t, s.Ended)
+		assert.Equal(t, []error{fooErr}, s.Errors)
+		v, _ := s.Attr(AttrAttempt)
+		assert.Equal(t, i+1, v)
+		d, ok := s.Attr(AttrBackoffDelay)
+		if i < 2 {
+			assert.True(t, ok)
+			assert.Equal(t, time.Microsecond.Seconds(), d)
+		} else {
+			assert.False(t, ok, "unexpected backoff delay on last attempt")
+		}
+	}
+}
+
+func TestRetryableTracerSuccess(t *testing.T) {
+	t.Parallel()
+	tr := &RecordingTracer{}
+	r := NewRetryable(3)
+	r.Tracer = tr
+
+	require.NoError(t, r.Retry(context.Background(), func(ctx context.Context) error {
+		return nil
+	}))
+	spans := tr.Spans()
+	require.Len(t, spans, 2)
+	assert.Empty(t, spans[0].Errors)
+	assert.Empty(t, spans[1].Errors)
+	v, _ := spans[0].Attr(AttrOutcome)
+	assert.Equal(t, "success", v)
+}
@@ -0,0 +1,123 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ValidationError reports all the problems found by a Validate method.
+type ValidationError struct {
+	// Subject names what was validated (e.g. "backoff").
+	Subject string
+	// Problems holds an error describing each problem found.
+	Problems []error
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Error()
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Subject, strings.Join(msgs, "; "))
+}
+
+// validator collects problems for a ValidationError.
+type validator struct {
+	problems []error
+}
+
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Errorf(format, args...))
+	}
+}
+
+// merge adds the problems reported by err, which may be a *ValidationError.
+func (v *validator) merge(err error) {
+	if err == nil {
+		return
+	}
+	if ve, ok := err.(*ValidationError); ok {
+		v.problems = append(v.problems, ve.Problems...)
+		return
+	}
+	v.problems = append(v.problems, err)
+}
+
+// err returns a *ValidationError for subject if any problems were found.
+func (v *validator) err(subject string) error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Subject: subject, Problems: v.problems}
+}
+
+// Validate returns a *ValidationError reporting every problem with the
+// receiver's parameters: negative durations, MinBackoff > MaxBackoff, a
+// Jitter outside [0, 1], a non-finite ExpFactor or one less than 1 (if
+// MinBackoff < MaxBackoff), or an unknown JitterMode.
+func (b *Backoff) Validate() error {
+	v := validator{}
+	v.check(b.MinBackoff >= 0, "MinBackoff (%s) is negative", b.MinBackoff)
+	v.check(b.MaxBackoff >= 0, "MaxBackoff (%s) is negative", b.MaxBackoff)
+	v.merge(b.checkRange())
+	v.check(b.Jitter >= 0 && b.Jitter <= 1, "Jitter (%v) is not in [0, 1]", b.Jitter)
+	if math.IsNaN(b.ExpFactor) || math.IsInf(b.ExpFactor, 0) {
+		v.check(false, "ExpFactor (%v) is not finite", b.ExpFactor)
+	} else {
+		v.check(b.ExpFactor >= 1 || b.MinBackoff >= b.MaxBackoff,
+			"ExpFactor (%v) < 1 with MinBackoff < MaxBackoff", b.ExpFactor)
+	}
+	v.check(b.JitterMode <= JitterEqual, "JitterMode (%d) is unknown", b.JitterMode)
+	return v.err("backoff")
+}
+
+// Validate returns a *ValidationError reporting every problem with the
+// receiver's parameters: negative durations, a MinBackoff that isn't
+// positive, or MinBackoff > MaxBackoff.
+func (d *DecorrelatedJitter) Validate() error {
+	v := validator{}
+	v.check(d.MinBackoff > 0, "MinBackoff (%s) is not positive", d.MinBackoff)
+	v.check(d.MaxBackoff >= 0, "MaxBackoff (%s) is negative", d.MaxBackoff)
+	v.check(d.MinBackoff <= d.MaxBackoff, "MinBackoff (%s) > MaxBackoff (%s)",
+		d.MinBackoff, d.MaxBackoff)
+	return v.err("decorrelated jitter")
+}
+
+// Validate returns a *ValidationError reporting every problem with the
+// receiver's parameters, including those reported by B.Validate (or, if
+// Strategy is set, by Strategy's Validate method if it has one), a MaxSteps
+// that isn't positive, and negative durations or error retention limits.
+//
+// Retry, Hedge, Poll and Attempts call Validate before making any attempts,
+// returning its error if there is one.
+func (r *Retryable) Validate() error {
+	v := validator{}
+	if r.Strategy == nil {
+		v.merge(r.B.Validate())
+	} else if s, ok := r.Strategy.(interface{ Validate() error }); ok {
+		v.merge(s.Validate())
+	}
+	v.check(r.MaxSteps > 0, "MaxSteps (%d) is not positive", r.MaxSteps)
+	v.check(r.MaxElapsed >= 0, "MaxElapsed (%s) is negative", r.MaxElapsed)
+	v.check(r.AttemptTimeout >= 0, "AttemptTimeout (%s) is negative", r.AttemptTimeout)
+	v.check(r.ErrorRetention.First >= 0, "ErrorRetention.First (%d) is negative", r.ErrorRetention.First)
+	v.check(r.ErrorRetention.Last >= 0, "ErrorRetention.Last (%d) is negative", r.ErrorRetention.Last)
+	return v.err("retryable")
+}
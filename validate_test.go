@@ -0,0 +1,115 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffValidate(t *testing.T) {
+	t.Parallel()
+	b := DefaultBackoff()
+	assert.NoError(t, b.Validate())
+	// A constant backoff doesn't need to grow.
+	assert.NoError(t, (&Backoff{MinBackoff: time.Second, MaxBackoff: time.Second}).Validate())
+
+	b = Backoff{
+		MinBackoff: -time.Second,
+		MaxBackoff: -2 * time.Second,
+		Jitter:     1.5,
+		ExpFactor:  math.Inf(1),
+		JitterMode: JitterMode(7),
+	}
+	err := b.Validate()
+	valErr := &ValidationError{}
+	require.True(t, errors.As(err, &valErr))
+	assert.Equal(t, "backoff", valErr.Subject)
+	assert.Len(t, valErr.Problems, 6)
+	assert.EqualError(t, err, "invalid backoff: MinBackoff (-1s) is negative; "+
+		"MaxBackoff (-2s) is negative; MinBackoff (-1s) > MaxBackoff (-2s); "+
+		"Jitter (1.5) is not in [0, 1]; ExpFactor (+Inf) is not finite; "+
+		"JitterMode (7) is unknown")
+
+	b = Backoff{MinBackoff: time.Millisecond, MaxBackoff: time.Second, Jitter: math.NaN(), ExpFactor: 0.5}
+	assert.EqualError(t, b.Validate(), "invalid backoff: Jitter (NaN) is not in [0, 1]; "+
+		"ExpFactor (0.5) < 1 with MinBackoff < MaxBackoff")
+}
+
+func TestDecorrelatedJitterValidate(t *testing.T) {
+	t.Parallel()
+	d := DefaultDecorrelatedJitter()
+	assert.NoError(t, d.Validate())
+	d = DecorrelatedJitter{MaxBackoff: -time.Second}
+	assert.EqualError(t, d.Validate(), "invalid decorrelated jitter: MinBackoff (0s) is not positive; "+
+		"MaxBackoff (-1s) is negative; MinBackoff (0s) > MaxBackoff (-1s)")
+}
+
+func TestRetryableValidate(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, NewRetryable(3).Validate())
+
+	r := Retryable{
+		B:              Backoff{MinBackoff: time.Second, MaxBackoff: time.Millisecond, ExpFactor: 2},
+		MaxElapsed:     -time.Second,
+		AttemptTimeout: -time.Millisecond,
+		ErrorRetention: ErrorRetention{First: -1},
+	}
+	assert.EqualError(t, r.Validate(), "invalid retryable: MinBackoff (1s) > MaxBackoff (1ms); "+
+		"MaxSteps (0) is not positive; MaxElapsed (-1s) is negative; "+
+		"AttemptTimeout (-1ms) is negative; ErrorRetention.First (-1) is negative")
+
+	// A Strategy's Validate method is used in place of B's.
+	r = Retryable{Strategy: &DecorrelatedJitter{MaxBackoff: time.Second}, MaxSteps: 1}
+	assert.EqualError(t, r.Validate(), "invalid retryable: MinBackoff (0s) is not positive")
+	// constantStrategy has no Validate method.
+	r.Strategy = &constantStrategy{d: time.Second}
+	assert.NoError(t, r.Validate())
+}
+
+func TestRetryValidatesUpFront(t *testing.T) {
+	t.Parallel()
+	err := Retry(context.Background(), DefaultBackoff(), 0, func(ctx context.Context) error {
+		t.Error("should not be called with zero steps")
+		return nil
+	})
+	assert.EqualError(t, err, "invalid retryable: MaxSteps (0) is not positive")
+
+	r := NewRetryable(3)
+	r.B.Jitter = -1
+	err = r.Hedge(context.Background(), func(ctx context.Context) error {
+		t.Error("should not be called with negative jitter")
+		return nil
+	})
+	assert.EqualError(t, err, "invalid retryable: Jitter (-1) is not in [0, 1]")
+}
+
+func TestRetryableConfigValidate(t *testing.T) {
+	t.Parallel()
+	cfg := NewRetryable(3).Config()
+	assert.NoError(t, cfg.Validate())
+
+	var empty RetryableConfig
+	err := json.Unmarshal([]byte(`{"backoff": {"min_backoff": "1s", "max_backoff": "1s"}}`), &empty)
+	assert.EqualError(t, err, "invalid retryable: MaxSteps (0) is not positive")
+	assert.Equal(t, RetryableConfig{}, empty)
+}
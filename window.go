@@ -0,0 +1,84 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import "time"
+
+// slidingWindow accumulates a pair of counters over a sliding window of
+// time, approximated by a ring of fixed-width buckets.
+type slidingWindow struct {
+	width   time.Duration
+	buckets []windowBucket
+}
+
+type windowBucket struct {
+	// epoch is the index of the bucket-width interval (since the Unix
+	// epoch) this bucket last accumulated counts for.
+	epoch int64
+	a, b  uint64
+}
+
+// newSlidingWindow returns a slidingWindow covering window, split into n
+// buckets. A non-positive window results in a single, never-expiring bucket.
+func newSlidingWindow(window time.Duration, n int) slidingWindow {
+	if n < 1 {
+		n = 1
+	}
+	width := window / time.Duration(n)
+	if width <= 0 {
+		width = 0
+		n = 1
+	}
+	return slidingWindow{width: width, buckets: make([]windowBucket, n)}
+}
+
+func (w *slidingWindow) epoch(now time.Time) int64 {
+	if w.width == 0 {
+		return 0
+	}
+	return now.UnixNano() / int64(w.width)
+}
+
+// add adds a and b to the counters at time now.
+func (w *slidingWindow) add(now time.Time, a, b uint64) {
+	e := w.epoch(now)
+	n := int64(len(w.buckets))
+	bkt := &w.buckets[int((e%n+n)%n)]
+	if bkt.epoch != e {
+		*bkt = windowBucket{epoch: e}
+	}
+	bkt.a += a
+	bkt.b += b
+}
+
+// sums returns the totals of both counters over the window ending at now.
+func (w *slidingWindow) sums(now time.Time) (a, b uint64) {
+	e := w.epoch(now)
+	oldest := e - int64(len(w.buckets)) + 1
+	for _, bkt := range w.buckets {
+		if bkt.epoch >= oldest && bkt.epoch <= e {
+			a += bkt.a
+			b += bkt.b
+		}
+	}
+	return a, b
+}
+
+// reset clears all counts.
+func (w *slidingWindow) reset() {
+	for i := range w.buckets {
+		w.buckets[i] = windowBucket{}
+	}
+}
@@ -0,0 +1,55 @@
+//   Copyright 2025 Vimeo
+//
+//   Licensed under the Apache License, Version 2.0 (the "License");
+//   you may not use this file except in compliance with the License.
+//   You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+//   Unless required by applicable law or agreed to in writing, software
+//   distributed under the License is distributed on an "AS IS" BASIS,
+//   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//   See the License for the specific language governing permissions and
+//   limitations under the License.
+
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	w := newSlidingWindow(10*time.Second, 10)
+
+	w.add(now, 1, 2)
+	w.add(now.Add(500*time.Millisecond), 1, 1)
+	w.add(now.Add(5*time.Second), 0, 4)
+	if a, b := w.sums(now.Add(5 * time.Second)); a != 2 || b != 7 {
+		t.Errorf("sums after 5s: got (%d, %d); want (2, 7)", a, b)
+	}
+	// the first bucket falls out of the window
+	if a, b := w.sums(now.Add(10 * time.Second)); a != 0 || b != 4 {
+		t.Errorf("sums after 10s: got (%d, %d); want (0, 4)", a, b)
+	}
+	// re-using the first bucket's slot resets it
+	w.add(now.Add(20*time.Second), 3, 3)
+	if a, b := w.sums(now.Add(20 * time.Second)); a != 3 || b != 3 {
+		t.Errorf("sums after 20s: got (%d, %d); want (3, 3)", a, b)
+	}
+	w.reset()
+	if a, b := w.sums(now.Add(20 * time.Second)); a != 0 || b != 0 {
+		t.Errorf("sums after reset: got (%d, %d); want (0, 0)", a, b)
+	}
+}
+
+func TestSlidingWindowUnbounded(t *testing.T) {
+	now := time.Unix(1000, 0)
+	w := newSlidingWindow(0, 10)
+	w.add(now, 1, 1)
+	w.add(now.Add(time.Hour), 1, 1)
+	if a, b := w.sums(now.Add(24 * time.Hour)); a != 2 || b != 2 {
+		t.Errorf("got (%d, %d); want (2, 2)", a, b)
+	}
+}